@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/AbolfazlAkhtari/weather-forecast/configs/db"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/app/weather"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+func main() {
+	city := flag.String("city", "", "name of the city to backfill (required)")
+	country := flag.String("country", "", "ISO 3166-1 alpha-2 code of the country of the city")
+	fromInput := flag.String("from", "", "first day to backfill, as YYYY-MM-DD in UTC (required)")
+	toInput := flag.String("to", "", "last day to backfill, as YYYY-MM-DD in UTC, yesterday by default")
+	providers := flag.String("providers", "", "comma separated providers asked for history, WEATHER_PROVIDERS by default")
+	flag.Parse()
+
+	input, err := parseInput(*city, *country, *fromInput, *toInput, *providers)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	database, err := db.Postgres{}.Open(true)
+	if err != nil {
+		log.Fatal("could not open the database: ", err)
+	}
+
+	// an interrupted chunk isn't stored, and running the command again resumes from it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Backfilling %s from %s to %s\n", input.CityName, input.From.Format(time.DateOnly), input.To.AddDate(0, 0, -1).Format(time.DateOnly))
+
+	output, err := weather.NewService(database).Backfill(ctx, input, func(progress weather.BackfillProgress) {
+		fmt.Printf("[%d/%d] %s to %s: %d stored, %d already stored\n",
+			progress.Done, progress.Total,
+			progress.From.Format(time.RFC3339), progress.To.Format(time.RFC3339),
+			progress.Stored, progress.Skipped,
+		)
+	})
+	if output != nil {
+		fmt.Printf("%s, %s: %d observations stored, %d already stored\n", output.Location.Name, output.Location.CountryCode, output.Stored, output.Skipped)
+	}
+	if err != nil {
+		log.Fatal(err, "\nrun the same command again to resume")
+	}
+}
+
+func parseInput(city, country, fromInput, toInput, providers string) (weather.BackfillInput, error) {
+	input := weather.BackfillInput{CityName: strings.TrimSpace(city), Country: strings.TrimSpace(country)}
+	if input.CityName == "" {
+		return input, fmt.Errorf("-city is required")
+	}
+
+	var err error
+	input.From, err = time.Parse(time.DateOnly, fromInput)
+	if err != nil {
+		return input, fmt.Errorf("-from must be a YYYY-MM-DD day: %w", err)
+	}
+
+	last := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	if toInput != "" {
+		last, err = time.Parse(time.DateOnly, toInput)
+		if err != nil {
+			return input, fmt.Errorf("-to must be a YYYY-MM-DD day: %w", err)
+		}
+	}
+
+	// the last day is backfilled whole
+	input.To = last.AddDate(0, 0, 1)
+	if !input.From.Before(input.To) {
+		return input, fmt.Errorf("-from must not be after -to")
+	}
+
+	if providers != "" {
+		input.Providers = strings.Split(providers, ",")
+	}
+
+	return input, nil
+}
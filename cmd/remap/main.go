@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/AbolfazlAkhtari/weather-forecast/configs/db"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/app/weather"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	database, err := db.Postgres{}.Open(true)
+	if err != nil {
+		log.Fatal("could not open the database: ", err)
+	}
+
+	// every record is stored as soon as it is remapped, and running the command again picks up the rest
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println("Remapping the stored weather records")
+
+	output, err := weather.NewService(database).RemapAll(ctx, func(progress weather.RemapOutput) {
+		fmt.Printf("%d remapped, %d failed, %d edited\n", progress.Remapped, progress.Failed, progress.Edited)
+	})
+	if output != nil {
+		fmt.Printf("%d records remapped, %d failed to map, %d edited meanwhile and left as is\n", output.Remapped, output.Failed, output.Edited)
+	}
+	if err != nil {
+		log.Fatal(err, "\nrun the same command again to resume")
+	}
+}
@@ -36,12 +36,12 @@ func main() {
 	httpRouter.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{config.AllowedOrigin},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{},
+		AllowedHeaders:   []string{"Accept", "Accept-Language", "Accept-Units", "Authorization", "Content-Type", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"Retry-After", "X-Cache", "X-Location-Cache"},
 		AllowCredentials: true,
 	}))
 
-	weather.NewController(database, httpRouter).InitRoutes()
+	weather.NewController(database, httpRouter, config).InitRoutes()
 
 	fmt.Printf("App Served on port %v \n\n", config.Port)
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%v", config.Port), httpRouter))
@@ -1,6 +1,8 @@
 package weather
 
 import (
+	"errors"
+	"fmt"
 	"github.com/caarlos0/env/v11"
 	"log"
 )
@@ -8,6 +10,11 @@ import (
 type Config struct {
 	Port          string `env:"WEATHER_PORT"`
 	AllowedOrigin string `env:"ALLOWED_ORIGIN"`
+	// AdminToken is the bearer token of the /admin routes, which are closed when it is empty
+	AdminToken string `env:"ADMIN_TOKEN"`
+	// PageSize is how many weathers a page of GET /weather lists unless page_size asks for up to MaxPageSize
+	PageSize    int `env:"WEATHER_PAGE_SIZE" envDefault:"10"`
+	MaxPageSize int `env:"WEATHER_MAX_PAGE_SIZE" envDefault:"100"`
 }
 
 func LoadFromEnv() Config {
@@ -16,5 +23,22 @@ func LoadFromEnv() Config {
 		log.Fatal("Error parsing weather config:", err)
 	}
 
+	if err := cfg.validate(); err != nil {
+		log.Fatal("Error parsing weather config:", err)
+	}
+
 	return cfg
 }
+
+// validate rejects the page sizes GET /weather can't list with.
+func (c Config) validate() error {
+	if c.PageSize < 1 || c.MaxPageSize < 1 {
+		return errors.New("WEATHER_PAGE_SIZE and WEATHER_MAX_PAGE_SIZE must be at least 1")
+	}
+
+	if c.PageSize > c.MaxPageSize {
+		return fmt.Errorf("WEATHER_PAGE_SIZE %d must be at most WEATHER_MAX_PAGE_SIZE %d", c.PageSize, c.MaxPageSize)
+	}
+
+	return nil
+}
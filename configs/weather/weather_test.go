@@ -0,0 +1,31 @@
+package weather
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		pageSize    int
+		maxPageSize int
+		valid       bool
+	}{
+		{name: "defaults", pageSize: 10, maxPageSize: 100, valid: true},
+		{name: "page size of the max", pageSize: 100, maxPageSize: 100, valid: true},
+		{name: "no page size", pageSize: 0, maxPageSize: 100},
+		{name: "no max page size", pageSize: 10, maxPageSize: 0},
+		{name: "page size above the max", pageSize: 20, maxPageSize: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Config{PageSize: tt.pageSize, MaxPageSize: tt.maxPageSize}.validate()
+
+			if tt.valid && err != nil {
+				t.Errorf("Config.validate() unexpected error = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Config.validate() error = nil, want an error")
+			}
+		})
+	}
+}
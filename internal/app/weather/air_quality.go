@@ -0,0 +1,68 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"gorm.io/gorm"
+)
+
+// fetchAirQuality returns the air quality fetched for input through the providers reporting it, and stored.
+// input.Mode is ignored, air quality being fetched from the first provider that answers.
+func (s Service) fetchAirQuality(ctx context.Context, input FetchDataInput) (*models.AirQuality, error) {
+	chain, err := s.providers.Chain(s.providerNames, s.config.ProviderTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := s.locationOf(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	location := mapFetchDataInputToLocation(input)
+	if l != nil {
+		location = mapLocationModelToLocation(*l)
+	}
+
+	response, err := chain.FetchAirQuality(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	a := mapFetchAirQualityResponseToAirQualityModel(*response)
+	if a.CityName == "" {
+		a.CityName, a.Country = input.CityName, input.Country
+	}
+
+	if l != nil {
+		a.CityName = l.Name
+		a.Country = l.CountryCode
+		a.LocationID = &l.ID
+	}
+
+	err = s.airQualities.Create(ctx, &a)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// latestAirQuality returns the latest air quality stored for the location known by cityName, or
+// for cityName itself when the location is unknown or records were fetched without it.
+func (s Service) latestAirQuality(ctx context.Context, cityName string) (*models.AirQuality, error) {
+	l, err := s.locations.FindByName(ctx, normalizeName(cityName), "")
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		a, err := s.airQualities.LatestByLocationID(ctx, l.ID)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return a, err
+		}
+	}
+
+	return s.airQualities.LatestByCityName(ctx, cityName)
+}
@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	weatherApiConf "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeAirQualityReporter struct {
+	fakeGeocoder
+}
+
+func (p fakeAirQualityReporter) FetchAirQuality(ctx context.Context, location weatherApiSchemata.Location) (*weatherApiSchemata.FetchAirQualityResponse, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+	if p.location != nil {
+		*p.location = location
+	}
+
+	pm25 := 8.5
+
+	return &weatherApiSchemata.FetchAirQualityResponse{
+		LocationName: location.CityName,
+		Country:      location.Country,
+		Latitude:     location.Coordinates.Latitude,
+		Longitude:    location.Coordinates.Longitude,
+		AQI:          2,
+		PM25:         &pm25,
+	}, nil
+}
+
+func TestService_fetchAirQuality(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	calls := 0
+	var location weatherApiSchemata.Location
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Current"})
+	service.providers.Register(fakeAirQualityReporter{fakeGeocoder{
+		fakeProvider: fakeProvider{name: "AirQuality", calls: &calls, location: &location},
+		places:       []weatherApiSchemata.Place{{Name: "London", Country: "GB", Latitude: 51.5074, Longitude: -0.1278}},
+	}})
+	service.providerNames = []weather_api.WeatherProvider{"Current", "AirQuality"}
+
+	result, err := service.fetchAirQuality(context.Background(), FetchDataInput{CityName: "Londres"})
+
+	t.Run("fetches and stores the air quality of the location", func(t *testing.T) {
+		require.NoError(t, err)
+		assert.Equal(t, "AirQuality", result.Provider)
+		assert.Equal(t, "London", result.CityName)
+		assert.Equal(t, "GB", result.Country)
+		require.NotNil(t, result.LocationID)
+		assert.Equal(t, 2, result.AQI)
+		assert.Equal(t, &weatherApiSchemata.Coordinates{Latitude: 51.5074, Longitude: -0.1278}, location.Coordinates)
+		assert.Equal(t, 1, calls)
+
+		var count int64
+		require.NoError(t, db.Model(&models.AirQuality{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("latest air quality is looked up by location", func(t *testing.T) {
+		latest, err := service.latestAirQuality(context.Background(), "LONDRES")
+
+		require.NoError(t, err)
+		assert.Equal(t, result.ID, latest.ID)
+		require.NotNil(t, latest.Location)
+		assert.Equal(t, "London", latest.Location.Name)
+	})
+
+	t.Run("latest air quality of a city never fetched", func(t *testing.T) {
+		_, err := service.latestAirQuality(context.Background(), "Paris")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("no provider reporting air quality", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"Current"}
+
+		latitude, longitude := 48.85, 2.35
+		result, err := service.fetchAirQuality(context.Background(), FetchDataInput{Latitude: &latitude, Longitude: &longitude})
+
+		assert.ErrorIs(t, err, weather_api.CapabilityNotSupportedErr)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,103 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// alertsByCityName returns the alerts of cityName, in country when it isn't empty, in effect or upcoming.
+// They are fetched through the providers issuing alerts and stored first, unless they were fetched within
+// the alerts TTL.
+func (s Service) alertsByCityName(ctx context.Context, cityName, country string) ([]models.Alert, error) {
+	key := normalizeName(cityName) + "|" + strings.ToUpper(strings.TrimSpace(country))
+
+	_, _, err := s.fetchedAlerts.Fetch(ctx, key, func(ctx context.Context) ([]models.Alert, error) {
+		return s.fetchAlerts(ctx, cityName, country)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	l, err := s.locations.FindByName(ctx, normalizeName(cityName), country)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.alerts.ListByCityName(ctx, cityName, country, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.alerts.ListByLocationID(ctx, l.ID, now)
+}
+
+// fetchAlerts returns the alerts of cityName fetched through the providers issuing alerts, and stored. The
+// stored alerts of the city the providers no longer issue end now.
+func (s Service) fetchAlerts(ctx context.Context, cityName, country string) ([]models.Alert, error) {
+	chain, err := s.providers.Chain(s.providerNames, s.config.ProviderTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	input := FetchDataInput{CityName: cityName, Country: country}
+
+	l, err := s.locationOf(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	location := mapFetchDataInputToLocation(input)
+	if l != nil {
+		location = mapLocationModelToLocation(*l)
+	}
+
+	response, err := chain.FetchAlerts(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := mapFetchAlertsResponseToAlertModels(*response)
+	keptIDs := make([]uuid.UUID, 0, len(alerts))
+	for i := range alerts {
+		if l != nil {
+			alerts[i].CityName = l.Name
+			alerts[i].Country = l.CountryCode
+			alerts[i].LocationID = &l.ID
+		}
+
+		err = s.alerts.Store(ctx, &alerts[i])
+		if err != nil {
+			return nil, err
+		}
+
+		keptIDs = append(keptIDs, alerts[i].ID)
+	}
+
+	// the stored alerts of the city missing from the response were withdrawn
+	var locationID *uuid.UUID
+	if l != nil {
+		locationID = &l.ID
+	}
+
+	cityCountry := response.Country
+	if cityCountry == "" {
+		cityCountry = country
+	}
+
+	err = s.alerts.Expire(ctx, locationID, response.LocationName, cityCountry, keptIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// activeAlerts returns the alerts in effect now, of every city they were fetched for.
+func (s Service) activeAlerts(ctx context.Context) ([]models.Alert, error) {
+	return s.alerts.ListActive(ctx, time.Now())
+}
@@ -0,0 +1,128 @@
+package weather
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/cache"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	weatherApiConf "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAlerter struct {
+	fakeGeocoder
+	alerts []weatherApiSchemata.Alert
+}
+
+func (p fakeAlerter) FetchAlerts(ctx context.Context, location weatherApiSchemata.Location) (*weatherApiSchemata.FetchAlertsResponse, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+
+	return &weatherApiSchemata.FetchAlertsResponse{
+		LocationName: location.CityName,
+		Country:      location.Country,
+		Latitude:     location.Coordinates.Latitude,
+		Longitude:    location.Coordinates.Longitude,
+		Alerts:       p.alerts,
+	}, p.err
+}
+
+func TestService_alertsByCityName(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+	service.fetchedAlerts = cache.New[[]models.Alert](time.Hour)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	calls := 0
+	alerter := fakeAlerter{
+		fakeGeocoder: fakeGeocoder{
+			fakeProvider: fakeProvider{name: "Alerts", calls: &calls},
+			places:       []weatherApiSchemata.Place{{Name: "London", Country: "GB", Latitude: 51.5074, Longitude: -0.1278}},
+		},
+		alerts: []weatherApiSchemata.Alert{
+			{Sender: "Met Office", Event: "Yellow wind warning", Severity: weatherApiSchemata.SeverityModerate, Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			{Sender: "Met Office", Event: "Yellow rain warning", Severity: weatherApiSchemata.SeverityModerate, Start: now.Add(time.Hour), End: now.Add(3 * time.Hour)},
+		},
+	}
+
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Current"})
+	service.providers.Register(alerter)
+	service.providerNames = []weather_api.WeatherProvider{"Current", "Alerts"}
+
+	t.Run("fetches and stores the alerts of the location", func(t *testing.T) {
+		result, err := service.alertsByCityName(context.Background(), "Londres", "")
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "Yellow wind warning", result[0].Event)
+		assert.Equal(t, "Alerts", result[0].Provider)
+		assert.Equal(t, "London", result[0].CityName)
+		require.NotNil(t, result[0].Location)
+		assert.Equal(t, "London", result[0].Location.Name)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("serves the fetched alerts until the TTL has passed", func(t *testing.T) {
+		result, err := service.alertsByCityName(context.Background(), "londres", "")
+
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("alerts fetched again are not stored twice", func(t *testing.T) {
+		service.fetchedAlerts = cache.New[[]models.Alert](0)
+
+		_, err := service.alertsByCityName(context.Background(), "London", "GB")
+		require.NoError(t, err)
+
+		var count int64
+		require.NoError(t, db.Model(&models.Alert{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("only alerts in effect are active", func(t *testing.T) {
+		result, err := service.activeAlerts(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Yellow wind warning", result[0].Event)
+	})
+
+	t.Run("alerts no longer issued end", func(t *testing.T) {
+		withdrawing := alerter
+		withdrawing.alerts = alerter.alerts[1:]
+		service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+		service.providers.Register(fakeProvider{name: "Current"})
+		service.providers.Register(withdrawing)
+
+		result, err := service.alertsByCityName(context.Background(), "London", "GB")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Yellow rain warning", result[0].Event)
+
+		active, err := service.activeAlerts(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, active)
+
+		var count int64
+		require.NoError(t, db.Model(&models.Alert{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("no provider issuing alerts", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"Current"}
+
+		_, err := service.alertsByCityName(context.Background(), "Paris", "")
+
+		assert.ErrorIs(t, err, weather_api.CapabilityNotSupportedErr)
+	})
+}
@@ -0,0 +1,120 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	"strings"
+	"time"
+)
+
+// backfillChunk is the span of history fetched and stored at once. A chunk is stored whole or not at
+// all, so an interrupted backfill resumes from the chunk it was storing.
+const backfillChunk = 7 * 24 * time.Hour
+
+// Backfill stores the hourly observations of the location known by input.CityName from input.From to
+// input.To, reporting progress after each chunk. Hours already stored are skipped, so a backfill can be
+// run again to resume it or extend its range. It is run by cmd/backfill.
+func (s Service) Backfill(ctx context.Context, input BackfillInput, progress func(BackfillProgress)) (*BackfillOutput, error) {
+	from, to := input.From.UTC().Truncate(time.Hour), input.To.UTC().Truncate(time.Hour)
+
+	l, err := s.resolveLocation(ctx, input.CityName, input.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	names := s.providerNames
+	if len(input.Providers) > 0 {
+		names = make([]weather_api.WeatherProvider, 0, len(input.Providers))
+		for _, name := range input.Providers {
+			names = append(names, weather_api.WeatherProvider(strings.TrimSpace(name)))
+		}
+	}
+
+	chain, err := s.providers.Chain(names, s.config.ProviderTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &BackfillOutput{Location: *l}
+	total := int((to.Sub(from) + backfillChunk - 1) / backfillChunk)
+
+	for start, done := from, 0; start.Before(to); start = start.Add(backfillChunk) {
+		end := start.Add(backfillChunk)
+		if end.After(to) {
+			end = to
+		}
+
+		stored, skipped, err := s.backfillChunk(ctx, chain, *l, start, end)
+		if err != nil {
+			return output, fmt.Errorf("could not backfill %s to %s: %w", start.Format(time.RFC3339), end.Format(time.RFC3339), err)
+		}
+
+		output.Stored += stored
+		output.Skipped += skipped
+		done++
+
+		progress(BackfillProgress{From: start, To: end, Stored: stored, Skipped: skipped, Done: done, Total: total})
+	}
+
+	return output, nil
+}
+
+// backfillChunk stores the observations of l from from to to not stored yet, only asking the providers
+// for them when some hour is missing. Observations backfilled meanwhile by a concurrent run are skipped
+// by the database.
+func (s Service) backfillChunk(ctx context.Context, chain weather_api.Chain, l models.Location, from, to time.Time) (stored, skipped int, err error) {
+	times, err := s.repository.ObservationTimes(ctx, l.ID, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// current weathers observed on the hour count too, whichever provider observed them
+	observed := make(map[int64]bool, len(times))
+	for _, t := range times {
+		if t.Equal(t.Truncate(time.Hour)) {
+			observed[t.Unix()] = true
+		}
+	}
+
+	if len(observed) == int(to.Sub(from)/time.Hour) {
+		return 0, len(observed), nil
+	}
+
+	response, err := chain.FetchHistory(ctx, mapLocationModelToLocation(l), from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	weathers := make([]models.Weather, 0, len(response.Observations))
+	for _, observation := range response.Observations {
+		if observed[observation.ObservedAt.Unix()] {
+			skipped++
+			continue
+		}
+
+		w := mapFetchWeatherResponseToWeatherModel(observation)
+		w.Provider = response.Provider
+		w.CityName = l.Name
+		w.Country = l.CountryCode
+		w.LocationID = &l.ID
+		// a backfilled observation is as old as when it was observed, so it is neither served as the latest
+		// weather nor taken for a fresh one
+		w.FetchedAt = *observation.ObservedAt
+		w.CreatedAt = *observation.ObservedAt
+
+		weathers = append(weathers, w)
+	}
+
+	if len(weathers) == 0 {
+		return 0, skipped, nil
+	}
+
+	created, err := s.repository.CreateBackfilled(ctx, weathers)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(created), skipped + len(weathers) - int(created), nil
+}
@@ -0,0 +1,101 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	weatherApiConf "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHistorian struct {
+	fakeProvider
+	// failAfter fails the calls after the first failAfter ones, when set
+	failAfter *int
+}
+
+func (p fakeHistorian) FetchHistory(ctx context.Context, location weatherApiSchemata.Location, from, to time.Time) (*weatherApiSchemata.FetchHistoryResponse, error) {
+	*p.calls++
+	if p.failAfter != nil && *p.calls > *p.failAfter {
+		return nil, errors.New("connection reset")
+	}
+
+	response := &weatherApiSchemata.FetchHistoryResponse{}
+	for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+		observedAt := hour
+		response.Observations = append(response.Observations, weatherApiSchemata.FetchWeatherResponse{
+			Temperature: 10,
+			Description: "clear sky",
+			Condition:   weatherApiSchemata.ConditionClear,
+			ObservedAt:  &observedAt,
+		})
+	}
+
+	return response, nil
+}
+
+func TestService_Backfill(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	paris := models.Location{Name: "Paris", CountryCode: "FR", Latitude: 48.85, Longitude: 2.35}
+	require.NoError(t, db.Create(&paris).Error)
+
+	calls, failAfter := 0, 1
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeHistorian{fakeProvider: fakeProvider{name: "History", calls: &calls}, failAfter: &failAfter})
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	input := BackfillInput{CityName: "Paris", From: from, To: from.AddDate(0, 0, 10), Providers: []string{"History"}}
+
+	var reported []BackfillProgress
+	progress := func(p BackfillProgress) {
+		reported = append(reported, p)
+	}
+
+	t.Run("interrupted", func(t *testing.T) {
+		output, err := service.Backfill(context.Background(), input, progress)
+
+		require.Error(t, err)
+		assert.Equal(t, 7*24, output.Stored, "the first chunk is stored")
+		require.Len(t, reported, 1)
+		assert.Equal(t, BackfillProgress{From: from, To: from.AddDate(0, 0, 7), Stored: 7 * 24, Done: 1, Total: 2}, reported[0])
+	})
+
+	t.Run("resumed", func(t *testing.T) {
+		failAfter = calls + 1
+		reported = nil
+
+		output, err := service.Backfill(context.Background(), input, progress)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3*24, output.Stored)
+		assert.Equal(t, 7*24, output.Skipped)
+		assert.Equal(t, 3, calls, "stored chunks aren't fetched again")
+		require.Len(t, reported, 2)
+	})
+
+	t.Run("run again", func(t *testing.T) {
+		output, err := service.Backfill(context.Background(), input, progress)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, output.Stored)
+		assert.Equal(t, 10*24, output.Skipped)
+		assert.Equal(t, 3, calls)
+	})
+
+	var count int64
+	require.NoError(t, db.Model(&models.Weather{}).Where("location_id = ?", paris.ID).Count(&count).Error)
+	assert.Equal(t, int64(10*24), count)
+
+	latest, err := service.latestWeather(context.Background(), "Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "History", latest.Provider)
+	assert.True(t, latest.CreatedAt.Equal(from.AddDate(0, 0, 10).Add(-time.Hour)), "backfilled weathers are as old as observed")
+}
@@ -1,57 +1,193 @@
 package weather
 
 import (
+	"fmt"
+	weatherCfg "github.com/AbolfazlAkhtari/weather-forecast/configs/weather"
 	httpErr "github.com/AbolfazlAkhtari/weather-forecast/internal/pkg/http"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/schemata"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/httpreq"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/httpres"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/middleware"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/url"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/validation"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 	"github.com/go-chi/chi/v5"
 	"gorm.io/gorm"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type Controller struct {
 	db      *gorm.DB
 	service Service
 	router  *chi.Mux
+	config  weatherCfg.Config
 }
 
-func NewController(db *gorm.DB, router *chi.Mux) Controller {
+func NewController(db *gorm.DB, router *chi.Mux, config weatherCfg.Config) Controller {
 	return Controller{
 		db:      db,
 		service: NewService(db),
 		router:  router,
+		config:  config,
 	}
 }
 
 func (c Controller) InitRoutes() {
 	c.router.Group(func(router chi.Router) {
 		router.Get("/weather", c.paginatedList)
+		router.Get("/weather/providers", c.providerStatuses)
 		router.Get("/weather/latest/{city_name}", c.getByCityName)
+		router.Get("/weather/forecast/{city_name}", c.getForecastByCityName)
+		router.Post("/weather/air-quality", c.fetchAirQuality)
+		router.Get("/weather/air-quality/latest/{city_name}", c.getAirQualityByCityName)
 		router.Get("/weather/{id}", c.getById)
+		router.Get("/weather/{id}/raw", c.getRawById)
 		router.Post("/weather", c.fetchData)
 		router.Put("/weather/{id}", c.update)
 		router.Delete("/weather/{id}", c.deleteById)
+		router.Get("/locations/reverse", c.reverseGeocode)
+		router.Get("/locations/search", c.searchLocations)
+		router.Get("/alerts", c.getAlertsByCityName)
+		router.Get("/alerts/active", c.getActiveAlerts)
+	})
+
+	c.router.Group(func(router chi.Router) {
+		router.Use(middleware.AdminOnly(c.config.AdminToken))
+
+		router.Get("/admin/providers/quota", c.providerQuotas)
 	})
 }
 
 func (c Controller) paginatedList(w http.ResponseWriter, r *http.Request) {
-	page := 1
+	presentation := getPresentation(w, r)
+	if presentation == nil {
+		return
+	}
+
+	input := ListInput{
+		Page:         1,
+		PageSize:     c.config.PageSize,
+		MaxPageSize:  c.config.MaxPageSize,
+		Presentation: *presentation,
+	}
+
+	filter := getListFilter(w, r)
+	if filter == nil {
+		return
+	}
+
+	input.Filter = *filter
+
+	var err error
+	input.Sort, err = parseSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		msg := err.Error()
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return
+	}
 
 	pageInput := r.URL.Query().Get("page")
 	if pageInput != "" {
+		input.Page, err = strconv.Atoi(pageInput)
+
+		if err != nil {
+			msg := "page must be a number"
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return
+		}
+	}
+
+	if pageSizeInput := r.URL.Query().Get("page_size"); pageSizeInput != "" {
+		input.PageSize, err = strconv.Atoi(pageSizeInput)
+
+		if err != nil {
+			msg := "page_size must be a number"
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return
+		}
+	}
+
+	if validationErrors := validation.ValidateData(input); validationErrors != nil {
+		// the validator's message names no limit, MaxPageSize not being part of the request
+		if _, ok := validationErrors["PageSize"]; ok {
+			validationErrors["PageSize"] = fmt.Sprintf("page_size must be between 1 and %d", input.MaxPageSize)
+		}
+
+		httpres.SendResponse(w, http.StatusUnprocessableEntity, validationErrors, nil)
+		return
+	}
+
+	input.Cursor = r.URL.Query().Get("cursor")
+	if input.Cursor != "" && (pageInput != "" || input.Sort != nil) {
+		msg := "cursor can't be given with page or sort"
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return
+	}
+
+	if countInput := r.URL.Query().Get("count"); countInput != "" {
+		count, err := strconv.ParseBool(countInput)
+
+		if err != nil {
+			msg := "count must be true or false"
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return
+		}
+
+		input.Count = &count
+	}
+
+	if input.Cursor != "" {
+		output, err := c.service.keysetList(r.Context(), input)
+		if err != nil {
+			handleServiceErrors(w, err)
+			return
+		}
+
+		output.Pagination.Next = pageLink(r, output.Next)
+		output.Pagination.Prev = pageLink(r, output.Prev)
+
+		httpres.SendResponse(w, http.StatusOK, output, nil)
+		return
+	}
+
+	output, err := c.service.paginatedList(r.Context(), input)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	output.Pagination.Next = pageLink(r, output.Next)
+	output.Pagination.Prev = pageLink(r, output.Prev)
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) providerStatuses(w http.ResponseWriter, r *http.Request) {
+	var check bool
+	if checkInput := r.URL.Query().Get("check"); checkInput != "" {
 		var err error
-		page, err = strconv.Atoi(pageInput)
+		check, err = strconv.ParseBool(checkInput)
 
 		if err != nil {
-			msg := err.Error()
+			msg := "check must be true or false"
 			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
 			return
 		}
 	}
 
-	output, err := c.service.paginatedList(r.Context(), page)
+	output := c.service.providerStatuses(r.Context(), check)
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) providerQuotas(w http.ResponseWriter, r *http.Request) {
+	output, err := c.service.providerQuotas(r.Context())
 	if err != nil {
 		handleServiceErrors(w, err)
 		return
@@ -66,7 +202,116 @@ func (c Controller) getByCityName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	output, err := c.service.latestByCityName(r.Context(), *cityName)
+	presentation := getPresentation(w, r)
+	if presentation == nil {
+		return
+	}
+
+	input := LatestInput{Presentation: *presentation}
+
+	maxAgeInput := r.URL.Query().Get("max_age")
+	if maxAgeInput != "" {
+		seconds, err := strconv.Atoi(maxAgeInput)
+		if err != nil || seconds < 0 {
+			msg := "max_age must be a non-negative number of seconds"
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return
+		}
+
+		maxAge := time.Duration(seconds) * time.Second
+		input.MaxAge = &maxAge
+	}
+
+	strictInput := r.URL.Query().Get("strict")
+	if strictInput != "" {
+		var err error
+		input.Strict, err = strconv.ParseBool(strictInput)
+
+		if err != nil {
+			msg := err.Error()
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return
+		}
+	}
+
+	output, err := c.service.latestByCityName(r.Context(), *cityName, input)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) getForecastByCityName(w http.ResponseWriter, r *http.Request) {
+	cityName := url.GetStringFromParam(r, w, "city_name")
+	if cityName == nil {
+		return
+	}
+
+	presentation := getPresentation(w, r)
+	if presentation == nil {
+		return
+	}
+
+	output, err := c.service.forecastByCityName(r.Context(), *cityName, r.URL.Query().Get("country"), *presentation)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) fetchAirQuality(w http.ResponseWriter, r *http.Request) {
+	input := httpreq.ParseAndValidateInput[FetchDataInput](w, r)
+	if input == nil {
+		return
+	}
+
+	output, err := c.service.fetchAirQuality(r.Context(), *input)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	httpres.SendResponse(w, http.StatusCreated, output, nil)
+}
+
+func (c Controller) getAirQualityByCityName(w http.ResponseWriter, r *http.Request) {
+	cityName := url.GetStringFromParam(r, w, "city_name")
+	if cityName == nil {
+		return
+	}
+
+	output, err := c.service.latestAirQuality(r.Context(), *cityName)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) getAlertsByCityName(w http.ResponseWriter, r *http.Request) {
+	cityName := strings.TrimSpace(r.URL.Query().Get("city"))
+	if cityName == "" {
+		msg := "city is required"
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return
+	}
+
+	output, err := c.service.alertsByCityName(r.Context(), cityName, r.URL.Query().Get("country"))
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) getActiveAlerts(w http.ResponseWriter, r *http.Request) {
+	output, err := c.service.activeAlerts(r.Context())
 	if err != nil {
 		handleServiceErrors(w, err)
 		return
@@ -81,7 +326,27 @@ func (c Controller) getById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	output, err := c.service.findById(r.Context(), *id)
+	presentation := getPresentation(w, r)
+	if presentation == nil {
+		return
+	}
+
+	output, err := c.service.weatherById(r.Context(), *id, *presentation)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) getRawById(w http.ResponseWriter, r *http.Request) {
+	id := url.GetUUIDFromParam(r, w, "id")
+	if id == nil {
+		return
+	}
+
+	output, err := c.service.rawById(r.Context(), *id)
 	if err != nil {
 		handleServiceErrors(w, err)
 		return
@@ -111,15 +376,96 @@ func (c Controller) fetchData(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	output, err := c.service.fetchData(r.Context(), *input)
+	output, cacheStatus, err := c.service.fetchData(r.Context(), *input)
 	if err != nil {
 		handleServiceErrors(w, err)
 		return
 	}
 
+	// a record served from the cache is still answered 201, X-Cache telling it apart
+	w.Header().Set("X-Cache", string(cacheStatus))
+
 	httpres.SendResponse(w, http.StatusCreated, output, nil)
 }
 
+func (c Controller) reverseGeocode(w http.ResponseWriter, r *http.Request) {
+	var input ReverseGeocodeInput
+
+	latitude, err := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	if err != nil || math.IsNaN(latitude) || math.Abs(latitude) > 90 {
+		msg := "lat must be a number between -90 and 90"
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return
+	}
+
+	longitude, err := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	if err != nil || math.IsNaN(longitude) || math.Abs(longitude) > 180 {
+		msg := "lon must be a number between -180 and 180"
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return
+	}
+
+	input.Latitude, input.Longitude = latitude, longitude
+
+	weatherInput := r.URL.Query().Get("weather")
+	if weatherInput != "" {
+		input.Weather, err = strconv.ParseBool(weatherInput)
+
+		if err != nil {
+			msg := err.Error()
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return
+		}
+	}
+
+	output, locationCacheStatus, cacheStatus, err := c.service.reverseGeocode(r.Context(), input)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	w.Header().Set("X-Location-Cache", string(locationCacheStatus))
+	if input.Weather {
+		w.Header().Set("X-Cache", string(cacheStatus))
+	}
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
+func (c Controller) searchLocations(w http.ResponseWriter, r *http.Request) {
+	input := SearchLocationsInput{
+		Query:   strings.TrimSpace(r.URL.Query().Get("q")),
+		Country: r.URL.Query().Get("country"),
+		Limit:   defaultSearchLimit,
+	}
+
+	if utf8.RuneCountInString(input.Query) < minSearchLength {
+		msg := fmt.Sprintf("q must be at least %d characters", minSearchLength)
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return
+	}
+
+	limitInput := r.URL.Query().Get("limit")
+	if limitInput != "" {
+		var err error
+		input.Limit, err = strconv.Atoi(limitInput)
+
+		if err != nil || input.Limit < 1 || input.Limit > maxSearchLimit {
+			msg := fmt.Sprintf("limit must be a number between 1 and %d", maxSearchLimit)
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return
+		}
+	}
+
+	output, err := c.service.searchLocations(r.Context(), input)
+	if err != nil {
+		handleServiceErrors(w, err)
+		return
+	}
+
+	httpres.SendResponse(w, http.StatusOK, output, nil)
+}
+
 func (c Controller) update(w http.ResponseWriter, r *http.Request) {
 	id := url.GetUUIDFromParam(r, w, "id")
 	if id == nil {
@@ -140,9 +486,217 @@ func (c Controller) update(w http.ResponseWriter, r *http.Request) {
 	httpres.SendResponse(w, http.StatusOK, output, nil)
 }
 
+// getPresentation reads how the weather asked for is to be presented from the query and headers, answering
+// 400 and returning nil when it can't be.
+func getPresentation(w http.ResponseWriter, r *http.Request) *Presentation {
+	units := getUnits(w, r)
+	if units == nil {
+		return nil
+	}
+
+	return &Presentation{Units: *units, Language: getLanguage(w, r)}
+}
+
+// getLanguage returns the language descriptions are answered in, negotiated from the Accept-Language header.
+func getLanguage(w http.ResponseWriter, r *http.Request) string {
+	w.Header().Add("Vary", "Accept-Language")
+
+	lang := matchLanguage(r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", lang)
+
+	return lang
+}
+
+// pageLink returns the URL of the list r asks for paged by cursor, empty when cursor is.
+func pageLink(r *http.Request, cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+
+	query := r.URL.Query()
+	query.Del("page")
+	query.Set("cursor", cursor)
+
+	return r.URL.Path + "?" + query.Encode()
+}
+
+// maxDescriptionFilterLength bounds the description searched by GET /weather, like descriptions are
+const maxDescriptionFilterLength = 200
+
+// getListFilter reads the weathers GET /weather lists from the query, answering 400 and returning nil
+// when it can't be.
+func getListFilter(w http.ResponseWriter, r *http.Request) *schemata.ListFilter {
+	query := r.URL.Query()
+
+	filter := schemata.ListFilter{
+		CityName: strings.TrimSpace(query.Get("city")),
+		Country:  strings.TrimSpace(query.Get("country")),
+		Provider: strings.TrimSpace(query.Get("provider")),
+		// searched as given, since spaces may be part of it
+		Description: query.Get("description"),
+	}
+
+	if utf8.RuneCountInString(filter.Description) > maxDescriptionFilterLength {
+		msg := fmt.Sprintf("description must be at most %d characters", maxDescriptionFilterLength)
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return nil
+	}
+
+	conditions := make([]string, len(weatherApiSchemata.Conditions))
+	for i, condition := range weatherApiSchemata.Conditions {
+		conditions[i] = string(condition)
+	}
+
+	intensities := make([]string, len(weatherApiSchemata.Intensities))
+	for i, intensity := range weatherApiSchemata.Intensities {
+		intensities[i] = string(intensity)
+	}
+
+	var ok bool
+	if filter.Condition, ok = getChoice(w, r, "condition", conditions); !ok {
+		return nil
+	}
+	if filter.Intensity, ok = getChoice(w, r, "intensity", intensities); !ok {
+		return nil
+	}
+	if filter.MinTemperature, filter.MaxTemperature, ok = getRange(w, r, "temperature", parseNumber); !ok {
+		return nil
+	}
+	if filter.MinHumidity, filter.MaxHumidity, ok = getRange(w, r, "humidity", strconv.Atoi); !ok {
+		return nil
+	}
+	if filter.MinWindSpeed, filter.MaxWindSpeed, ok = getRange(w, r, "wind_speed", parseNumber); !ok {
+		return nil
+	}
+	if filter.FetchedFrom, filter.FetchedBefore, ok = getDateRange(w, r, "fetched"); !ok {
+		return nil
+	}
+
+	return &filter
+}
+
+// parseNumber parses a finite float.
+func parseNumber(value string) (float64, error) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err == nil && (math.IsNaN(number) || math.IsInf(number, 0)) {
+		return 0, strconv.ErrRange
+	}
+
+	return number, err
+}
+
+// getRange returns the min_<name> and max_<name> query parameters parsed by parse, nil when they aren't set.
+// It answers 400 and returns false when either can't be parsed or the range is empty.
+func getRange[T int | float64](w http.ResponseWriter, r *http.Request, name string, parse func(string) (T, error)) (low, high *T, ok bool) {
+	bounds := [2]*T{}
+
+	for i, param := range []string{"min_" + name, "max_" + name} {
+		value := strings.TrimSpace(r.URL.Query().Get(param))
+		if value == "" {
+			continue
+		}
+
+		bound, err := parse(value)
+		if err != nil {
+			msg := fmt.Sprintf("%s must be a number", param)
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return nil, nil, false
+		}
+
+		bounds[i] = &bound
+	}
+
+	low, high = bounds[0], bounds[1]
+	if low != nil && high != nil && *low > *high {
+		msg := fmt.Sprintf("min_%s must not be greater than max_%s", name, name)
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return nil, nil, false
+	}
+
+	return low, high, true
+}
+
+// getDateRange returns the start of the <name>_from date query parameter and the end of the <name>_to one, both
+// UTC and nil when they aren't set. It answers 400 and returns false when either isn't a date or the range is empty.
+func getDateRange(w http.ResponseWriter, r *http.Request, name string) (from, before *time.Time, ok bool) {
+	dates := [2]*time.Time{}
+
+	for i, param := range []string{name + "_from", name + "_to"} {
+		value := strings.TrimSpace(r.URL.Query().Get(param))
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			msg := fmt.Sprintf("%s must be a date like %s", param, time.DateOnly)
+			httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+			return nil, nil, false
+		}
+
+		dates[i] = &date
+	}
+
+	from = dates[0]
+	if dates[1] != nil {
+		end := dates[1].AddDate(0, 0, 1)
+		before = &end
+	}
+
+	if from != nil && before != nil && !from.Before(*before) {
+		msg := fmt.Sprintf("%s_from must not be after %s_to", name, name)
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return nil, nil, false
+	}
+
+	return from, before, true
+}
+
+// getChoice returns the value of the query parameter param, which must be one of choices when set. It answers
+// 400 and returns false when it isn't.
+func getChoice(w http.ResponseWriter, r *http.Request, param string, choices []string) (string, bool) {
+	value := strings.ToLower(strings.TrimSpace(r.URL.Query().Get(param)))
+	if value == "" || slices.Contains(choices, value) {
+		return value, true
+	}
+
+	msg := fmt.Sprintf("%s must be one of %s or %s", param, strings.Join(choices[:len(choices)-1], ", "), choices[len(choices)-1])
+	httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+
+	return "", false
+}
+
+// getUnits returns the unit system asked for by the units query parameter, or else the Accept-Units
+// header, metric when neither is set. It answers 400 and returns nil when the system is unknown.
+func getUnits(w http.ResponseWriter, r *http.Request) *string {
+	w.Header().Add("Vary", "Accept-Units")
+
+	units := r.URL.Query().Get("units")
+	if units == "" {
+		units = r.Header.Get("Accept-Units")
+	}
+
+	units = strings.ToLower(strings.TrimSpace(units))
+	if units == "" {
+		units = UnitsMetric
+	}
+
+	if _, ok := unitSystems[units]; !ok {
+		msg := fmt.Sprintf("units must be one of %s, %s or %s", UnitsMetric, UnitsImperial, UnitsStandard)
+		httpres.SendResponse(w, http.StatusBadRequest, nil, &msg)
+		return nil
+	}
+
+	return &units
+}
+
 func handleServiceErrors(w http.ResponseWriter, err error) {
 	status := httpErr.MapErrorToHttpStatusCode(err)
 
+	if retryAfter, ok := httpErr.RetryAfter(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	}
+
 	msg := err.Error()
 	httpres.SendResponse(w, status, nil, &msg)
 }
@@ -0,0 +1,145 @@
+package weather
+
+import (
+	_ "embed"
+	"encoding/json"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"golang.org/x/text/language"
+	"sort"
+)
+
+//go:embed translations/descriptions.json
+var descriptionsJSON []byte
+
+// descriptions holds the translations of the condition descriptions of each provider, by
+// provider name, condition code and ISO 639-1 language code.
+var descriptions = loadDescriptions()
+
+// descriptionLanguages lists the languages descriptions are available in, fetchLanguage first
+var descriptionLanguages = loadDescriptionLanguages()
+
+var descriptionLanguageMatcher = language.NewMatcher(descriptionLanguages)
+
+func loadDescriptions() map[string]map[int]map[string]string {
+	var table map[string]map[int]map[string]string
+	if err := json.Unmarshal(descriptionsJSON, &table); err != nil {
+		panic("invalid description translations: " + err.Error())
+	}
+
+	return table
+}
+
+func loadDescriptionLanguages() []language.Tag {
+	seen := map[string]bool{fetchLanguage: true}
+	var languages []string
+
+	for _, conditions := range descriptions {
+		for _, translations := range conditions {
+			for lang := range translations {
+				if !seen[lang] {
+					seen[lang] = true
+					languages = append(languages, lang)
+				}
+			}
+		}
+	}
+
+	sort.Strings(languages)
+
+	tags := []language.Tag{language.Make(fetchLanguage)}
+	for _, lang := range languages {
+		tags = append(tags, language.Make(lang))
+	}
+
+	return tags
+}
+
+// matchLanguage returns the language descriptions are available in best matching acceptLanguage, an
+// Accept-Language header value, fetchLanguage when none does.
+func matchLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return fetchLanguage
+	}
+
+	_, index, confidence := descriptionLanguageMatcher.Match(tags...)
+	if confidence == language.No {
+		return fetchLanguage
+	}
+
+	base, _ := descriptionLanguages[index].Base()
+
+	return base.String()
+}
+
+// translate returns the description of the condition code of provider in lang.
+func translate(provider string, code *int, lang string) (string, bool) {
+	if code == nil {
+		return "", false
+	}
+
+	description, ok := descriptions[provider][*code][lang]
+
+	return description, ok
+}
+
+// localizeWeather returns w with its description, and those of its readings, in lang when the
+// translations have them, as stored otherwise.
+func localizeWeather(w models.Weather, lang string) models.Weather {
+	if lang == "" || lang == fetchLanguage {
+		return w
+	}
+
+	stored := w.Description
+	if description, ok := translate(w.Provider, w.ConditionCode, lang); ok {
+		w.Description = description
+	}
+
+	if w.Readings == nil {
+		return w
+	}
+
+	// the readings array is shared with w, which may be cached
+	readings := make([]models.WeatherReading, len(w.Readings))
+	translated := false
+
+	for i, reading := range w.Readings {
+		if description, ok := translate(reading.Provider, reading.ConditionCode, lang); ok {
+			// a consensus description has no code of its own, it is the description of some of its readings
+			if w.ConditionCode == nil && !translated && reading.Description == stored {
+				w.Description = description
+				translated = true
+			}
+
+			reading.Description = description
+		}
+
+		readings[i] = reading
+	}
+
+	w.Readings = readings
+
+	return w
+}
+
+// localizeForecast returns f with the descriptions of its entries in lang when the translations have
+// them, as stored otherwise.
+func localizeForecast(f models.Forecast, lang string) models.Forecast {
+	if lang == "" || lang == fetchLanguage {
+		return f
+	}
+
+	// the entries array is shared with the stored forecast
+	entries := make([]models.ForecastEntry, len(f.Entries))
+	for i, entry := range f.Entries {
+		if description, ok := translate(f.Provider, entry.ConditionCode, lang); ok {
+			entry.Description = description
+		}
+
+		entries[i] = entry
+	}
+
+	f.Entries = entries
+
+	return f
+}
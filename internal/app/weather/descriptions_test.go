@@ -0,0 +1,78 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "no header", acceptLanguage: "", expected: "en"},
+		{name: "supported language", acceptLanguage: "fr", expected: "fr"},
+		{name: "regional variant", acceptLanguage: "de-AT,de;q=0.9", expected: "de"},
+		{name: "by quality", acceptLanguage: "ja;q=0.9,es;q=0.8", expected: "es"},
+		{name: "unsupported language", acceptLanguage: "ja", expected: "en"},
+		{name: "invalid header", acceptLanguage: "@@@", expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchLanguage(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestLocalizeWeather(t *testing.T) {
+	clear, partlyCloudy := 800, 2
+
+	t.Run("single provider", func(t *testing.T) {
+		w := models.Weather{Provider: "OpenWeather", Description: "clear sky", ConditionCode: &clear}
+
+		assert.Equal(t, "ciel dégagé", localizeWeather(w, "fr").Description)
+		assert.Equal(t, "clear sky", localizeWeather(w, "en").Description)
+		assert.Equal(t, "clear sky", localizeWeather(w, "").Description)
+	})
+
+	t.Run("without condition code", func(t *testing.T) {
+		w := models.Weather{Provider: "OpenWeather", Description: "sunny all day"}
+
+		assert.Equal(t, "sunny all day", localizeWeather(w, "fr").Description)
+	})
+
+	t.Run("consensus", func(t *testing.T) {
+		readings := []models.WeatherReading{
+			{Provider: "OpenWeather", Description: "clear sky", ConditionCode: &clear},
+			{Provider: "OpenMeteo", Description: "partly cloudy", ConditionCode: &partlyCloudy},
+		}
+		w := models.Weather{Provider: "Consensus", Description: "partly cloudy", Readings: readings}
+
+		result := localizeWeather(w, "de")
+
+		assert.Equal(t, "teilweise bewölkt", result.Description)
+		assert.Equal(t, "klarer Himmel", result.Readings[0].Description)
+		assert.Equal(t, "teilweise bewölkt", result.Readings[1].Description)
+		assert.Equal(t, "clear sky", readings[0].Description, "stored readings are left as is")
+	})
+}
+
+func TestLocalizeForecast(t *testing.T) {
+	lightRain := 500
+	entries := []models.ForecastEntry{
+		{Description: "light rain", ConditionCode: &lightRain},
+		{Description: "stored before codes were kept"},
+	}
+	f := models.Forecast{Provider: "OpenWeather", Entries: entries}
+
+	result := localizeForecast(f, "fr")
+
+	assert.Equal(t, "pluie légère", result.Entries[0].Description)
+	assert.Equal(t, "stored before codes were kept", result.Entries[1].Description)
+	assert.Equal(t, "light rain", entries[0].Description, "stored entries are left as is")
+	assert.Equal(t, f, localizeForecast(f, "en"))
+}
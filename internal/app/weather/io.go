@@ -1,14 +1,31 @@
 package weather
 
 import (
+	"fmt"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/schemata"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
 	"time"
 )
 
+const (
+	// FetchModeChain serves the fetch from the first provider of the chain that answers
+	FetchModeChain = "chain"
+	// FetchModeConsensus queries every provider and stores the merge of their readings
+	FetchModeConsensus = "consensus"
+)
+
 type FetchDataInput struct {
-	CityName string `json:"city_name" validate:"required"`
+	CityName string `json:"city_name" validate:"required_without_all=Latitude LocationID"`
 	Country  string `json:"country"`
+	// LocationID picks a known location, such as one found by GET /locations/reverse; city_name and country are then ignored
+	LocationID *uuid.UUID `json:"location_id"`
+	// Latitude and Longitude locate the weather unambiguously; when set, city_name and country only label it
+	Latitude  *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+	Mode      string   `json:"mode" validate:"omitempty,oneof=chain consensus"`
 }
 
 type UpdateInput struct {
@@ -18,14 +35,212 @@ type UpdateInput struct {
 	Description *string    `json:"description,omitempty" validate:"omitempty,max=200"`
 	Humidity    *int       `json:"humidity,omitempty" validate:"omitempty,gte=0,lte=100"`
 	WindSpeed   *float64   `json:"wind_speed,omitempty" validate:"omitempty,gte=0"`
+	Pressure    *int       `json:"pressure,omitempty" validate:"omitempty,gt=0"`
+	WindDeg     *int       `json:"wind_deg,omitempty" validate:"omitempty,gte=0,lte=360"`
+	WindGust    *float64   `json:"wind_gust,omitempty" validate:"omitempty,gte=0"`
+	FeelsLike   *float64   `json:"feels_like,omitempty" validate:"omitempty"`
+	TempMin     *float64   `json:"temp_min,omitempty" validate:"omitempty"`
+	TempMax     *float64   `json:"temp_max,omitempty" validate:"omitempty"`
+	Visibility  *int       `json:"visibility,omitempty" validate:"omitempty,gte=0"`
+	Clouds      *int       `json:"clouds,omitempty" validate:"omitempty,gte=0,lte=100"`
+	Rain1h      *float64   `json:"rain_1h,omitempty" validate:"omitempty,gte=0"`
+	Rain3h      *float64   `json:"rain_3h,omitempty" validate:"omitempty,gte=0"`
+	Snow1h      *float64   `json:"snow_1h,omitempty" validate:"omitempty,gte=0"`
+	Snow3h      *float64   `json:"snow_3h,omitempty" validate:"omitempty,gte=0"`
+	Sunrise     *time.Time `json:"sunrise,omitempty"`
+	Sunset      *time.Time `json:"sunset,omitempty"`
+	ObservedAt  *time.Time `json:"observed_at,omitempty"`
 	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 }
 
+// Presentation tells how read endpoints present weather.
+type Presentation struct {
+	// Units is the unit system of the output, metric when empty
+	Units string
+	// Language is the language of descriptions, as stored when empty
+	Language string
+}
+
+type LatestInput struct {
+	Presentation
+	// MaxAge is how old the latest weather may be before it is refreshed, nil to serve it however old
+	MaxAge *time.Duration
+	// Strict fetches a fresh weather before answering instead of serving a stale one
+	Strict bool
+}
+
+type LatestOutput struct {
+	models.Weather
+	// Stale is set when the weather is older than the requested max age, while it is refreshed in background
+	Stale bool  `json:"stale"`
+	Units Units `json:"units"`
+}
+
+type WeatherOutput struct {
+	models.Weather
+	Units Units `json:"units"`
+}
+
+type ForecastOutput struct {
+	models.Forecast
+	Units Units `json:"units"`
+}
+
+type ReverseGeocodeInput struct {
+	Latitude  float64
+	Longitude float64
+	// Weather also fetches the weather of the location found, as POST /weather does
+	Weather bool
+}
+
+type ReverseGeocodeOutput struct {
+	Location models.Location `json:"location"`
+	Weather  *models.Weather `json:"weather,omitempty"`
+}
+
+type SearchLocationsInput struct {
+	Query string
+	// Country narrows the search down to a country, empty to search everywhere
+	Country string
+	Limit   int
+}
+
+type LocationCandidateOutput struct {
+	// LocationID is set on known locations, which POST /weather accepts
+	LocationID  *uuid.UUID `json:"location_id"`
+	Name        string     `json:"name"`
+	CountryCode string     `json:"country_code"`
+	State       string     `json:"state"`
+	Latitude    float64    `json:"latitude"`
+	Longitude   float64    `json:"longitude"`
+	// Match is MatchExact, MatchPrefix, MatchFuzzy or MatchProvider
+	Match string `json:"match"`
+}
+
+// parseSort parses a comma separated list of schemata.SortFields, each descending when prefixed with a minus,
+// e.g. "-temperature,city_name".
+func parseSort(sort string) ([]schemata.SortField, error) {
+	var fields []schemata.SortField
+
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+
+		sortField := schemata.SortField{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if !slices.Contains(schemata.SortFields, sortField.Field) {
+			return nil, fmt.Errorf("sort field %s must be one of %s", sortField.Field, strings.Join(schemata.SortFields, ", "))
+		}
+		if slices.ContainsFunc(fields, func(f schemata.SortField) bool { return f.Field == sortField.Field }) {
+			return nil, fmt.Errorf("sort field %s is given twice", sortField.Field)
+		}
+
+		fields = append(fields, sortField)
+	}
+
+	return fields, nil
+}
+
 type ListInput struct {
-	Page int `json:"page"`
+	Page int `json:"page" validate:"min=1"`
+	// PageSize is how many weathers a page lists, at most MaxPageSize
+	PageSize int `json:"page_size" validate:"min=1,ltefield=MaxPageSize"`
+	// MaxPageSize is the configured largest page size
+	MaxPageSize int `json:"-"`
+	// Filter only lists the weathers matching it, its temperature and wind speed ranges being in Presentation's units
+	Filter schemata.ListFilter
+	// Sort orders the weathers, newest first when empty
+	Sort []schemata.SortField
+	// Cursor is the token of a page linked to by a previous one, paging through the weathers newest first
+	// instead of Page
+	Cursor string
+	// Count counts the weathers listed, which numbered pages are unless it is false and cursor pages only
+	// when it is true
+	Count *bool
+	Presentation
 }
 
 type ListOutput struct {
 	Weathers   []models.Weather    `json:"data"`
 	Pagination schemata.Pagination `json:"pagination"`
+	Units      Units               `json:"units"`
+	// Next and Prev are the cursors of the neighbour pages, linked to by Pagination
+	Next string `json:"-"`
+	Prev string `json:"-"`
+}
+
+type CursorListOutput struct {
+	Weathers   []models.Weather          `json:"data"`
+	Pagination schemata.CursorPagination `json:"pagination"`
+	Units      Units                     `json:"units"`
+	// Next and Prev are the cursors of the neighbour pages, linked to by Pagination
+	Next string `json:"-"`
+	Prev string `json:"-"`
+}
+
+type ProviderStatusOutput struct {
+	Name         string   `json:"name"`
+	Capabilities []string `json:"capabilities"`
+	// Position is the provider's 1-based position in the fallback chain, 0 when it is not part of it
+	Position     int    `json:"position"`
+	CircuitState string `json:"circuit_state"`
+	// Healthy and HealthError are only set when the providers were checked
+	Healthy     *bool  `json:"healthy,omitempty"`
+	HealthError string `json:"health_error,omitempty"`
+}
+
+type ProviderQuotaOutput struct {
+	Name              string `json:"name"`
+	RequestsPerMinute int    `json:"requests_per_minute"`
+	AvailableTokens   int    `json:"available_tokens"`
+	DailyQuota        int64  `json:"daily_quota"`
+	UsedToday         int64  `json:"used_today"`
+	// RemainingToday is -1 when the provider has no daily quota
+	RemainingToday int64 `json:"remaining_today"`
+}
+
+type RawOutput struct {
+	ID        uuid.UUID `json:"id"`
+	Provider  string    `json:"provider"`
+	FetchedAt time.Time `json:"fetched_at"`
+	// Payload is the provider response body verbatim, or a list of provider and payload pairs on consensus records
+	Payload models.RawPayload `json:"payload"`
+}
+
+type RemapOutput struct {
+	// Remapped is the number of records mapped again from their raw payload
+	Remapped int
+	// Failed is the number of records whose payload could not be mapped, left unchanged
+	Failed int
+	// Edited is the number of records edited while they were remapped, left unchanged
+	Edited int
+}
+
+type BackfillInput struct {
+	CityName string
+	Country  string
+	// From and To bound the hours backfilled, From included and To excluded
+	From time.Time
+	To   time.Time
+	// Providers are the providers asked for history in order, those of the configured chain when empty
+	Providers []string
+}
+
+// BackfillProgress is reported once a chunk of a backfill is stored.
+type BackfillProgress struct {
+	From time.Time
+	To   time.Time
+	// Stored is the number of observations stored from the chunk, Skipped the number already stored before
+	Stored  int
+	Skipped int
+	// Done chunks out of Total
+	Done  int
+	Total int
+}
+
+type BackfillOutput struct {
+	Location models.Location
+	Stored   int
+	Skipped  int
 }
@@ -0,0 +1,66 @@
+package weather
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/schemata"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name          string
+		sort          string
+		expected      []schemata.SortField
+		expectedError string
+	}{
+		{name: "empty", sort: "", expected: nil},
+		{name: "fields", sort: "-temperature, City_Name", expected: []schemata.SortField{{Field: "temperature", Desc: true}, {Field: "city_name"}}},
+		{name: "unknown field", sort: "raw", expectedError: "sort field raw must be one of " + strings.Join(schemata.SortFields, ", ")},
+		{name: "injection", sort: "temperature;DROP TABLE weathers", expectedError: "sort field temperature;drop table weathers must be one of " + strings.Join(schemata.SortFields, ", ")},
+		{name: "field given twice", sort: "humidity,-humidity", expectedError: "sort field humidity is given twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseSort(tt.sort)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestListInput_Validation(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          ListInput
+		expectedFields []string
+	}{
+		{name: "valid", input: ListInput{Page: 1, PageSize: 10, MaxPageSize: 100}},
+		{name: "page size of the max", input: ListInput{Page: 3, PageSize: 100, MaxPageSize: 100}},
+		{name: "page 0", input: ListInput{Page: 0, PageSize: 10, MaxPageSize: 100}, expectedFields: []string{"Page"}},
+		{name: "negative page size", input: ListInput{Page: 1, PageSize: -1, MaxPageSize: 100}, expectedFields: []string{"PageSize"}},
+		{name: "page size above the max", input: ListInput{Page: -2, PageSize: 101, MaxPageSize: 100}, expectedFields: []string{"Page", "PageSize"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validation.ValidateData(tt.input)
+
+			var fields []string
+			for field := range errs {
+				fields = append(fields, field)
+			}
+			assert.ElementsMatch(t, tt.expectedFields, fields)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/cache"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"slices"
+	"strings"
+)
+
+// sameLocationDelta is the distance, in degrees of latitude and longitude, within which a
+// geocoded place of the same country is taken for an already known location.
+const sameLocationDelta = 0.05
+
+// normalizeName lowercases name and collapses its whitespace, the form aliases are stored in.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// resolveLocation returns the location known by cityName in country, geocoding it through the
+// providers and storing it the first time it is asked for. A place geocoded next to a known
+// location is the same one, and cityName is remembered as one of its aliases.
+func (s Service) resolveLocation(ctx context.Context, cityName, country string) (*models.Location, error) {
+	name := normalizeName(cityName)
+
+	l, err := s.locations.FindByName(ctx, name, country)
+	if err == nil {
+		return l, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	chain, err := s.providers.Chain(s.providerNames, s.config.ProviderTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := chain.Geocode(ctx, weatherApiSchemata.Location{CityName: cityName, Country: country})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storePlace(ctx, response.Places[0], name)
+}
+
+// storePlace returns the location of a geocoded place, the known location next to it or a new one,
+// remembering alias as one of its names unless it is empty or its name.
+func (s Service) storePlace(ctx context.Context, place weatherApiSchemata.Place, alias string) (*models.Location, error) {
+	l, err := s.locations.FindNear(ctx, place.Country, place.Latitude, place.Longitude, sameLocationDelta)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		fresh := mapPlaceToLocationModel(place)
+		if alias != "" && alias != normalizeName(fresh.Name) {
+			fresh.Aliases = []string{alias}
+		}
+
+		// a concurrent request may have stored the place since, which is then used
+		err = s.locations.CreateOrFind(ctx, &fresh)
+		l = &fresh
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if alias != "" && alias != normalizeName(l.Name) && !slices.Contains(l.Aliases, alias) {
+		err = s.locations.AddAlias(ctx, l.ID, alias)
+		if err != nil {
+			return nil, err
+		}
+
+		l.Aliases = append(l.Aliases, alias)
+	}
+
+	return l, nil
+}
+
+// locationOf returns the location input asks the weather of, nil when it can't be told: input
+// only has coordinates, or no provider could geocode it for now. Weather is then fetched by
+// the name or coordinates given, as before locations existed.
+func (s Service) locationOf(ctx context.Context, input FetchDataInput) (*models.Location, error) {
+	if input.LocationID != nil {
+		return s.locations.FindById(ctx, *input.LocationID)
+	}
+	if input.Latitude != nil {
+		return nil, nil
+	}
+
+	l, err := s.resolveLocation(ctx, input.CityName, input.Country)
+	if errors.Is(err, weather_api.CapabilityNotSupportedErr) || errors.Is(err, weather_api.ProvidersExhaustedErr) {
+		log.Printf("could not resolve location of %s: %v", input.CityName, err)
+
+		return nil, nil
+	}
+
+	return l, err
+}
+
+// latestWeather returns the latest weather stored for the location known by cityName, or
+// for cityName itself when the location is unknown or records predate it.
+func (s Service) latestWeather(ctx context.Context, cityName string) (*models.Weather, error) {
+	l, err := s.locations.FindByName(ctx, normalizeName(cityName), "")
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		w, err := s.repository.LatestByLocationID(ctx, l.ID)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return w, err
+		}
+	}
+
+	return s.repository.LatestByCityName(ctx, cityName)
+}
+
+// reverseGeocode returns the location nearest to input's coordinates, stored the first time a
+// provider names it, along with its weather fetched like fetchData does when input.Weather is set.
+// The location found is served again for coordinates within about 100 meters for a while: only its
+// ID is cached, the row being read again so that changes to it show. The cache statuses of the
+// location and of the weather are returned apart, the latter empty when no weather was asked for.
+func (s Service) reverseGeocode(ctx context.Context, input ReverseGeocodeInput) (*ReverseGeocodeOutput, cache.Status, cache.Status, error) {
+	key := fmt.Sprintf("%.3f,%.3f", input.Latitude, input.Longitude)
+
+	id, locationStatus, err := s.reverse.Fetch(ctx, key, func(ctx context.Context) (uuid.UUID, error) {
+		chain, err := s.providers.Chain(s.providerNames, s.config.ProviderTimeout)
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		response, err := chain.ReverseGeocode(ctx, weatherApiSchemata.Coordinates{Latitude: input.Latitude, Longitude: input.Longitude})
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		l, err := s.storePlace(ctx, response.Places[0], "")
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		return l.ID, nil
+	})
+	if err != nil {
+		return nil, locationStatus, "", err
+	}
+
+	l, err := s.locations.FindById(ctx, id)
+	if err != nil {
+		return nil, locationStatus, "", err
+	}
+
+	output := &ReverseGeocodeOutput{Location: *l}
+	if !input.Weather {
+		return output, locationStatus, "", nil
+	}
+
+	var weatherStatus cache.Status
+	output.Weather, weatherStatus, err = s.fetchData(ctx, FetchDataInput{LocationID: &l.ID})
+	if err != nil {
+		return nil, locationStatus, weatherStatus, err
+	}
+
+	return output, locationStatus, weatherStatus, nil
+}
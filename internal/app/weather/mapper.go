@@ -3,19 +3,269 @@ package weather
 import (
 	"encoding/json"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 	"time"
 )
 
 func mapFetchWeatherResponseToWeatherModel(response schemata.FetchWeatherResponse) models.Weather {
-	return models.Weather{
-		CityName:    response.LocationName,
-		Country:     response.Country,
-		Temperature: response.Temperature,
-		Description: response.Description,
-		Humidity:    response.Humidity,
-		WindSpeed:   response.WindSpeed,
-		FetchedAt:   time.Now(),
+	w := models.Weather{
+		CityName:      response.LocationName,
+		Country:       response.Country,
+		Temperature:   response.Temperature,
+		Description:   response.Description,
+		ConditionCode: response.ConditionCode,
+		Condition:     string(response.Condition),
+		Intensity:     string(response.Intensity),
+		Humidity:      response.Humidity,
+		WindSpeed:     response.WindSpeed,
+		Pressure:      response.Pressure,
+		WindDeg:       response.WindDeg,
+		WindGust:      response.WindGust,
+		FeelsLike:     response.FeelsLike,
+		TempMin:       response.TempMin,
+		TempMax:       response.TempMax,
+		Visibility:    response.Visibility,
+		Clouds:        response.Clouds,
+		Rain1h:        response.Rain1h,
+		Rain3h:        response.Rain3h,
+		Snow1h:        response.Snow1h,
+		Snow3h:        response.Snow3h,
+		Sunrise:       response.Sunrise,
+		Sunset:        response.Sunset,
+		ObservedAt:    response.ObservedAt,
+		Provider:      response.Provider,
+		FetchedAt:     time.Now(),
+		Raw:           models.RawPayload(response.Raw),
+	}
+
+	if response.Coordinates != nil {
+		w.Latitude, w.Longitude = &response.Coordinates.Latitude, &response.Coordinates.Longitude
+	}
+
+	return w
+}
+
+func mapConsensusResponseToWeatherModel(response schemata.ConsensusResponse) models.Weather {
+	w := mapFetchWeatherResponseToWeatherModel(response.FetchWeatherResponse)
+	w.Disagreements = response.Disagreements
+
+	payloads := make([]models.ConsensusPayload, 0, len(response.Readings))
+	for _, reading := range response.Readings {
+		w.Readings = append(w.Readings, models.WeatherReading{
+			Provider:      reading.Provider,
+			Temperature:   reading.Temperature,
+			Description:   reading.Description,
+			ConditionCode: reading.ConditionCode,
+			Humidity:      reading.Humidity,
+			WindSpeed:     reading.WindSpeed,
+		})
+		payloads = append(payloads, models.ConsensusPayload{Provider: reading.Provider, Payload: reading.Raw})
+	}
+
+	// payloads only hold bodies providers answered with valid json, so marshalling them can't fail
+	w.Raw, _ = json.Marshal(payloads)
+
+	return w
+}
+
+// mapRemappedResponseToWeatherModel returns w with the fields mapped from its raw payload replaced by
+// response's, keeping what was not read from the payload: identity, name, location and fetch time.
+func mapRemappedResponseToWeatherModel(w models.Weather, response schemata.FetchWeatherResponse) models.Weather {
+	remapped := mapFetchWeatherResponseToWeatherModel(response)
+	remapped.ID = w.ID
+	remapped.CityName = w.CityName
+	remapped.Country = w.Country
+	remapped.Provider = w.Provider
+	remapped.LocationID = w.LocationID
+	remapped.Location = w.Location
+	remapped.FetchedAt = w.FetchedAt
+	remapped.CreatedAt = w.CreatedAt
+	remapped.Disagreements = w.Disagreements
+	remapped.Readings = w.Readings
+	remapped.Raw = w.Raw
+
+	return remapped
+}
+
+// mapRemappedConsensusToWeatherModel is mapRemappedResponseToWeatherModel for consensus records, also
+// updating the disagreements and the readings of w.
+func mapRemappedConsensusToWeatherModel(w models.Weather, response schemata.ConsensusResponse) models.Weather {
+	remapped := mapRemappedResponseToWeatherModel(w, response.FetchWeatherResponse)
+	remapped.Disagreements = response.Disagreements
+	remapped.Readings = make([]models.WeatherReading, len(w.Readings))
+
+	for i, stored := range w.Readings {
+		remapped.Readings[i] = stored
+
+		for _, reading := range response.Readings {
+			if reading.Provider == stored.Provider {
+				remapped.Readings[i].Temperature = reading.Temperature
+				remapped.Readings[i].Description = reading.Description
+				remapped.Readings[i].ConditionCode = reading.ConditionCode
+				remapped.Readings[i].Humidity = reading.Humidity
+				remapped.Readings[i].WindSpeed = reading.WindSpeed
+				break
+			}
+		}
+	}
+
+	return remapped
+}
+
+func mapFetchForecastResponseToForecastModel(response schemata.FetchForecastResponse, ttl time.Duration) models.Forecast {
+	now := time.Now()
+
+	f := models.Forecast{
+		CityName:  response.LocationName,
+		Country:   response.Country,
+		Provider:  response.Provider,
+		Entries:   make([]models.ForecastEntry, 0, len(response.Entries)),
+		FetchedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	for _, entry := range response.Entries {
+		f.Entries = append(f.Entries, models.ForecastEntry{
+			Time:          entry.Time,
+			Temperature:   entry.Temperature,
+			Description:   entry.Description,
+			Humidity:      entry.Humidity,
+			WindSpeed:     entry.WindSpeed,
+			ConditionCode: entry.ConditionCode,
+		})
+	}
+
+	return f
+}
+
+func mapFetchAirQualityResponseToAirQualityModel(response schemata.FetchAirQualityResponse) models.AirQuality {
+	return models.AirQuality{
+		CityName:   response.LocationName,
+		Country:    response.Country,
+		Latitude:   response.Latitude,
+		Longitude:  response.Longitude,
+		AQI:        response.AQI,
+		CO:         response.CO,
+		NO:         response.NO,
+		NO2:        response.NO2,
+		O3:         response.O3,
+		SO2:        response.SO2,
+		PM25:       response.PM25,
+		PM10:       response.PM10,
+		NH3:        response.NH3,
+		ObservedAt: response.ObservedAt,
+		Provider:   response.Provider,
+		FetchedAt:  time.Now(),
+	}
+}
+
+func mapFetchAlertsResponseToAlertModels(response schemata.FetchAlertsResponse) []models.Alert {
+	now := time.Now()
+
+	alerts := make([]models.Alert, 0, len(response.Alerts))
+	for _, a := range response.Alerts {
+		alerts = append(alerts, models.Alert{
+			CityName:    response.LocationName,
+			Country:     response.Country,
+			Latitude:    response.Latitude,
+			Longitude:   response.Longitude,
+			Provider:    response.Provider,
+			Sender:      a.Sender,
+			Event:       a.Event,
+			Severity:    string(a.Severity),
+			Description: a.Description,
+			Tags:        a.Tags,
+			StartsAt:    a.Start,
+			EndsAt:      a.End,
+			FetchedAt:   now,
+		})
+	}
+
+	return alerts
+}
+
+func mapFetchDataInputToLocation(input FetchDataInput) schemata.Location {
+	location := schemata.Location{
+		CityName: input.CityName,
+		Country:  input.Country,
+		Language: fetchLanguage,
+	}
+
+	if input.Latitude != nil && input.Longitude != nil {
+		location.Coordinates = &schemata.Coordinates{Latitude: *input.Latitude, Longitude: *input.Longitude}
+	}
+
+	return location
+}
+
+// mapLocationModelToLocation asks for the weather at the coordinates of l under its canonical name.
+func mapLocationModelToLocation(l models.Location) schemata.Location {
+	return schemata.Location{
+		CityName:    l.Name,
+		Country:     l.CountryCode,
+		Coordinates: &schemata.Coordinates{Latitude: l.Latitude, Longitude: l.Longitude},
+		Language:    fetchLanguage,
+	}
+}
+
+func mapPlaceToLocationModel(place schemata.Place) models.Location {
+	return models.Location{
+		Name:        place.Name,
+		CountryCode: place.Country,
+		State:       place.State,
+		Latitude:    place.Latitude,
+		Longitude:   place.Longitude,
+		Timezone:    place.Timezone,
+	}
+}
+
+func mapLocationModelToCandidateOutput(l models.Location, match string) LocationCandidateOutput {
+	return LocationCandidateOutput{
+		LocationID:  &l.ID,
+		Name:        l.Name,
+		CountryCode: l.CountryCode,
+		State:       l.State,
+		Latitude:    l.Latitude,
+		Longitude:   l.Longitude,
+		Match:       match,
+	}
+}
+
+func mapPlaceToCandidateOutput(place schemata.Place, match string) LocationCandidateOutput {
+	return LocationCandidateOutput{
+		Name:        place.Name,
+		CountryCode: place.Country,
+		State:       place.State,
+		Latitude:    place.Latitude,
+		Longitude:   place.Longitude,
+		Match:       match,
+	}
+}
+
+func mapProviderToStatusOutput(provider weather_api.Provider, position int, state weather_api.CircuitState) ProviderStatusOutput {
+	providerCapabilities := weather_api.Capabilities(provider)
+	capabilities := make([]string, 0, len(providerCapabilities))
+	for _, capability := range providerCapabilities {
+		capabilities = append(capabilities, string(capability))
+	}
+
+	return ProviderStatusOutput{
+		Name:         provider.Name(),
+		Capabilities: capabilities,
+		Position:     position,
+		CircuitState: string(state),
+	}
+}
+
+func mapQuotaStatusToQuotaOutput(name string, status weather_api.QuotaStatus) ProviderQuotaOutput {
+	return ProviderQuotaOutput{
+		Name:              name,
+		RequestsPerMinute: status.RequestsPerMinute,
+		AvailableTokens:   status.AvailableTokens,
+		DailyQuota:        status.DailyQuota,
+		UsedToday:         status.UsedToday,
+		RemainingToday:    status.RemainingToday,
 	}
 }
 
@@ -10,6 +10,8 @@ import (
 )
 
 func TestMapFetchWeatherResponseToWeatherModel(t *testing.T) {
+	newYorkLatitude, newYorkLongitude := 40.7128, -74.006
+
 	tests := []struct {
 		name     string
 		response schemata.FetchWeatherResponse
@@ -20,16 +22,21 @@ func TestMapFetchWeatherResponseToWeatherModel(t *testing.T) {
 			response: schemata.FetchWeatherResponse{
 				LocationName: "New York",
 				Country:      "US",
+				Coordinates:  &schemata.Coordinates{Latitude: 40.7128, Longitude: -74.006},
 				Temperature:  25.5,
 				Description:  "Sunny",
+				Condition:    schemata.ConditionClear,
 				Humidity:     65,
 				WindSpeed:    10.2,
 			},
 			expected: models.Weather{
 				CityName:    "New York",
 				Country:     "US",
+				Latitude:    &newYorkLatitude,
+				Longitude:   &newYorkLongitude,
 				Temperature: 25.5,
 				Description: "Sunny",
+				Condition:   "clear",
 				Humidity:    65,
 				WindSpeed:   10.2,
 			},
@@ -104,6 +111,8 @@ func TestMapFetchWeatherResponseToWeatherModel(t *testing.T) {
 			assert.Equal(t, tt.expected.Description, result.Description)
 			assert.Equal(t, tt.expected.Humidity, result.Humidity)
 			assert.Equal(t, tt.expected.WindSpeed, result.WindSpeed)
+			assert.Equal(t, tt.expected.Latitude, result.Latitude, "coordinates stay nil when not reported")
+			assert.Equal(t, tt.expected.Longitude, result.Longitude)
 
 			// Check that FetchedAt is set to a recent time
 			assert.WithinDuration(t, time.Now(), result.FetchedAt, 2*time.Second)
@@ -111,6 +120,72 @@ func TestMapFetchWeatherResponseToWeatherModel(t *testing.T) {
 	}
 }
 
+func TestMapConsensusResponseToWeatherModel(t *testing.T) {
+	response := schemata.ConsensusResponse{
+		FetchWeatherResponse: schemata.FetchWeatherResponse{
+			Provider:     "Consensus",
+			LocationName: "Berlin",
+			Country:      "DE",
+			Temperature:  15,
+			Description:  "clear sky",
+			Humidity:     61,
+			WindSpeed:    2.5,
+		},
+		Readings: []schemata.FetchWeatherResponse{
+			{Provider: "A", Temperature: 10, Description: "clear sky", Humidity: 60, WindSpeed: 2},
+			{Provider: "B", Temperature: 20, Description: "clear sky", Humidity: 62, WindSpeed: 3},
+		},
+		Disagreements: []string{"temperature"},
+	}
+
+	result := mapConsensusResponseToWeatherModel(response)
+
+	assert.Equal(t, "Berlin", result.CityName)
+	assert.Equal(t, "Consensus", result.Provider)
+	assert.Equal(t, 15.0, result.Temperature)
+	assert.Equal(t, []string{"temperature"}, result.Disagreements)
+	assert.Equal(t, []models.WeatherReading{
+		{Provider: "A", Temperature: 10, Description: "clear sky", Humidity: 60, WindSpeed: 2},
+		{Provider: "B", Temperature: 20, Description: "clear sky", Humidity: 62, WindSpeed: 3},
+	}, result.Readings)
+}
+
+func TestMapFetchDataInputToLocation(t *testing.T) {
+	latitude, longitude := 37.2153, -93.2982
+
+	tests := []struct {
+		name     string
+		input    FetchDataInput
+		expected schemata.Location
+	}{
+		{
+			name:     "city",
+			input:    FetchDataInput{CityName: "Springfield", Country: "US"},
+			expected: schemata.Location{CityName: "Springfield", Country: "US", Language: fetchLanguage},
+		},
+		{
+			name:  "coordinates",
+			input: FetchDataInput{CityName: "Springfield", Latitude: &latitude, Longitude: &longitude},
+			expected: schemata.Location{
+				CityName:    "Springfield",
+				Coordinates: &schemata.Coordinates{Latitude: latitude, Longitude: longitude},
+				Language:    fetchLanguage,
+			},
+		},
+		{
+			name:     "latitude without longitude",
+			input:    FetchDataInput{CityName: "Springfield", Latitude: &latitude},
+			expected: schemata.Location{CityName: "Springfield", Language: fetchLanguage},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mapFetchDataInputToLocation(tt.input))
+		})
+	}
+}
+
 func TestMapUpdateInputToRepoInput(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,22 @@
+package weather
+
+import "github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+
+// present returns w as presentation asks for.
+func present(w models.Weather, presentation Presentation) models.Weather {
+	return localizeWeather(convertWeather(w, presentation.Units), presentation.Language)
+}
+
+// presentForecast returns f as presentation asks for.
+func presentForecast(f models.Forecast, presentation Presentation) models.Forecast {
+	return localizeForecast(convertForecast(f, presentation.Units), presentation.Language)
+}
+
+func presentAll(weathers []models.Weather, presentation Presentation) []models.Weather {
+	presented := make([]models.Weather, len(weathers))
+	for i, w := range weathers {
+		presented[i] = present(w, presentation)
+	}
+
+	return presented
+}
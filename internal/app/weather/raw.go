@@ -0,0 +1,120 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+)
+
+// remapBatchSize is the number of records loaded at once while remapping
+const remapBatchSize = 100
+
+// rawById returns the provider payload the weather with id was mapped from.
+func (s Service) rawById(ctx context.Context, id uuid.UUID) (*RawOutput, error) {
+	w, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(w.Raw) == 0 {
+		return nil, fmt.Errorf("no raw payload stored for weather %s: %w", id, gorm.ErrRecordNotFound)
+	}
+
+	return &RawOutput{
+		ID:        w.ID,
+		Provider:  w.Provider,
+		FetchedAt: w.FetchedAt,
+		Payload:   w.Raw,
+	}, nil
+}
+
+// RemapAll maps every stored raw payload again with the current provider mappers and stores the
+// result, so fields added or fixed since the records were fetched are backfilled, reporting progress
+// after each batch. Edited records are left alone, their edits would be undone, and so are records
+// failing to map, which are logged. It is run by cmd/remap.
+func (s Service) RemapAll(ctx context.Context, progress func(RemapOutput)) (*RemapOutput, error) {
+	var output RemapOutput
+
+	for afterID := uuid.Nil; ; {
+		weathers, err := s.repository.ListRemappable(ctx, afterID, remapBatchSize)
+		if err != nil {
+			return &output, err
+		}
+
+		for _, w := range weathers {
+			saved, err := s.remap(ctx, w)
+			if err != nil {
+				log.Printf("could not remap weather %s: %v", w.ID, err)
+				output.Failed++
+				continue
+			}
+
+			if !saved {
+				output.Edited++
+				continue
+			}
+
+			output.Remapped++
+		}
+
+		progress(output)
+
+		if len(weathers) < remapBatchSize {
+			return &output, nil
+		}
+
+		afterID = weathers[len(weathers)-1].ID
+	}
+}
+
+// remap maps the raw payload of w again and stores the result unless w was edited in the meantime,
+// reporting whether it was stored.
+func (s Service) remap(ctx context.Context, w models.Weather) (bool, error) {
+	var remapped models.Weather
+
+	if w.Provider == weather_api.ConsensusProvider {
+		var payloads []models.ConsensusPayload
+		if err := json.Unmarshal(w.Raw, &payloads); err != nil {
+			return false, err
+		}
+
+		if len(payloads) == 0 {
+			return false, errors.New("no reading in consensus payload")
+		}
+
+		readings := make([]weatherApiSchemata.FetchWeatherResponse, 0, len(payloads))
+		for _, payload := range payloads {
+			reading, err := s.providers.MapCurrent(weather_api.WeatherProvider(payload.Provider), payload.Payload)
+			if err != nil {
+				return false, err
+			}
+
+			readings = append(readings, *reading)
+		}
+
+		remapped = mapRemappedConsensusToWeatherModel(w, weather_api.MergeReadings(readings, s.config.Consensus))
+	} else {
+		response, err := s.providers.MapCurrent(weather_api.WeatherProvider(w.Provider), json.RawMessage(w.Raw))
+		if err != nil {
+			return false, err
+		}
+
+		remapped = mapRemappedResponseToWeatherModel(w, *response)
+	}
+
+	saved, err := s.repository.SaveRemapped(ctx, &remapped)
+	if err != nil || !saved {
+		return false, err
+	}
+
+	s.forget(w.ID)
+
+	return true, nil
+}
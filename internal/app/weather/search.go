@@ -0,0 +1,235 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/google/uuid"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MatchExact, MatchPrefix and MatchFuzzy tell how a candidate's name, or one of its aliases, matches a search
+	MatchExact  = "exact"
+	MatchPrefix = "prefix"
+	MatchFuzzy  = "fuzzy"
+	// MatchProvider is a place the provider matched by other means, such as its name in another language
+	MatchProvider = "provider"
+)
+
+const (
+	// minSearchLength is the shortest search answered, shorter ones matching too many places
+	minSearchLength    = 2
+	defaultSearchLimit = 10
+	maxSearchLimit     = 20
+)
+
+// searchCandidates bounds the known locations starting like a search, and the ones sharing its first
+// letter that are matched against it for typos, since misspellings rarely start with the wrong letter.
+const searchCandidates = 500
+
+// matchRanks orders matches from best to worst
+var matchRanks = map[string]int{MatchExact: 0, MatchPrefix: 1, MatchFuzzy: 2, MatchProvider: 3}
+
+// searchLocations returns the known locations and the places the providers geocode input.Query to
+// matching it, best match first. Known locations win over the places next to them. Searching
+// works on known locations alone when no provider can geocode for now.
+func (s Service) searchLocations(ctx context.Context, input SearchLocationsInput) ([]LocationCandidateOutput, error) {
+	query := normalizeName(input.Query)
+
+	known, err := s.knownLocations(ctx, query, input)
+	if err != nil {
+		return nil, err
+	}
+
+	places, err := s.searchPlaces(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// typos ranks fuzzy matches among themselves, fewer first
+	var candidates []LocationCandidateOutput
+	var typos []int
+
+	for _, l := range known {
+		match, distance, ok := matchName(query, append([]string{l.Name}, l.Aliases...)...)
+		if ok {
+			candidates = append(candidates, mapLocationModelToCandidateOutput(l, match))
+			typos = append(typos, distance)
+		}
+	}
+
+	for _, place := range places {
+		if nearCandidate(candidates, place) {
+			continue
+		}
+
+		match, distance, ok := matchName(query, place.Name)
+		if !ok {
+			match, distance = MatchProvider, 0
+		}
+
+		candidates = append(candidates, mapPlaceToCandidateOutput(place, match))
+		typos = append(typos, distance)
+	}
+
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if matchRanks[candidates[a].Match] != matchRanks[candidates[b].Match] {
+			return matchRanks[candidates[a].Match] < matchRanks[candidates[b].Match]
+		}
+
+		return typos[a] < typos[b]
+	})
+
+	ranked := make([]LocationCandidateOutput, 0, min(len(order), input.Limit))
+	for _, i := range order[:min(len(order), input.Limit)] {
+		ranked = append(ranked, candidates[i])
+	}
+
+	return ranked, nil
+}
+
+// knownLocations returns the known locations named like query, or starting like it, and when they are
+// fewer than input.Limit the ones sharing its first letter, which may be misspelled like it.
+func (s Service) knownLocations(ctx context.Context, query string, input SearchLocationsInput) ([]models.Location, error) {
+	known, err := s.locations.ListByPrefix(ctx, query, input.Country, searchCandidates)
+	if err != nil || len(known) >= input.Limit {
+		return known, err
+	}
+
+	first, _ := utf8.DecodeRuneInString(query)
+	similar, err := s.locations.ListByPrefix(ctx, string(first), input.Country, searchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[uuid.UUID]bool, len(known))
+	for _, l := range known {
+		listed[l.ID] = true
+	}
+
+	for _, l := range similar {
+		if !listed[l.ID] {
+			known = append(known, l)
+		}
+	}
+
+	return known, nil
+}
+
+// searchPlaces returns the places the providers geocode input to, none when they know no such
+// place or can't geocode for now. Searches are cached since they are repeated while typing.
+func (s Service) searchPlaces(ctx context.Context, input SearchLocationsInput) ([]weatherApiSchemata.Place, error) {
+	key := normalizeName(input.Query) + "|" + strings.ToUpper(strings.TrimSpace(input.Country))
+
+	places, _, err := s.searches.Fetch(ctx, key, func(ctx context.Context) ([]weatherApiSchemata.Place, error) {
+		chain, err := s.providers.Chain(s.providerNames, s.config.ProviderTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := chain.Geocode(ctx, weatherApiSchemata.Location{CityName: input.Query, Country: input.Country})
+		if errors.Is(err, weather_api.NotFoundErr) {
+			return []weatherApiSchemata.Place{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return response.Places, nil
+	})
+	if errors.Is(err, weather_api.CapabilityNotSupportedErr) || errors.Is(err, weather_api.ProvidersExhaustedErr) {
+		log.Printf("could not search places named %s: %v", input.Query, err)
+
+		return nil, nil
+	}
+
+	return places, err
+}
+
+// nearCandidate reports whether place is one of candidates, found by another name or provider.
+func nearCandidate(candidates []LocationCandidateOutput, place weatherApiSchemata.Place) bool {
+	for _, c := range candidates {
+		if strings.EqualFold(c.CountryCode, place.Country) &&
+			math.Abs(c.Latitude-place.Latitude) <= sameLocationDelta && math.Abs(c.Longitude-place.Longitude) <= sameLocationDelta {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchName returns the best match of query, normalized, against names, and the number of typos
+// of fuzzy matches. A name matches fuzzily when it, or its start as long as query, is a typo
+// away from query for every 3 letters of query.
+func matchName(query string, names ...string) (match string, distance int, ok bool) {
+	maxTypos := utf8.RuneCountInString(query) / 3
+	best, bestDistance := "", maxTypos+1
+
+	for _, name := range names {
+		name = normalizeName(name)
+
+		switch {
+		case name == query:
+			return MatchExact, 0, true
+		case strings.HasPrefix(name, query):
+			best, bestDistance = MatchPrefix, 0
+		case best != MatchPrefix:
+			d := typoDistance(query, name)
+			if start := []rune(name); len(start) > utf8.RuneCountInString(query) {
+				d = min(d, typoDistance(query, string(start[:utf8.RuneCountInString(query)])))
+			}
+
+			if d < bestDistance {
+				best, bestDistance = MatchFuzzy, d
+			}
+		}
+	}
+
+	return best, bestDistance, best != ""
+}
+
+// typoDistance returns the number of single letter insertions, deletions, substitutions or swaps of
+// adjacent letters turning a into b.
+func typoDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// rows of the distances between the prefixes of a and b, ending with the current prefix of a
+	beforePrevious := make([]int, len(rb)+1)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				current[j] = min(current[j], beforePrevious[j-2]+1)
+			}
+		}
+
+		beforePrevious, previous, current = previous, current, beforePrevious
+	}
+
+	return previous[len(rb)]
+}
@@ -0,0 +1,60 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypoDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "london", b: "london", expected: 0},
+		{a: "lodnon", b: "london", expected: 1},
+		{a: "londn", b: "london", expected: 1},
+		{a: "lundon", b: "london", expected: 1},
+		{a: "paris", b: "london", expected: 6},
+		{a: "zürich", b: "zurich", expected: 1},
+		{a: "", b: "oslo", expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, typoDistance(tt.a, tt.b))
+		})
+	}
+}
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		names            []string
+		expectedMatch    string
+		expectedDistance int
+		expectedOk       bool
+	}{
+		{name: "exact", query: "london", names: []string{"London"}, expectedMatch: MatchExact, expectedOk: true},
+		{name: "exact alias", query: "londres", names: []string{"London", "londres"}, expectedMatch: MatchExact, expectedOk: true},
+		{name: "prefix", query: "lon", names: []string{"London"}, expectedMatch: MatchPrefix, expectedOk: true},
+		{name: "misspelled", query: "lodnon", names: []string{"London"}, expectedMatch: MatchFuzzy, expectedDistance: 1, expectedOk: true},
+		{name: "misspelled prefix", query: "lomd", names: []string{"London"}, expectedMatch: MatchFuzzy, expectedDistance: 1, expectedOk: true},
+		{name: "too many typos", query: "lxndxx", names: []string{"London"}},
+		{name: "short queries are not fuzzy", query: "lo", names: []string{"Oslo"}},
+		{name: "prefix wins over fuzzy", query: "pari", names: []string{"Parma", "Paris"}, expectedMatch: MatchPrefix, expectedOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, distance, ok := matchName(tt.query, tt.names...)
+
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedMatch, match)
+			if ok {
+				assert.Equal(t, tt.expectedDistance, distance)
+			}
+		})
+	}
+}
@@ -2,55 +2,406 @@ package weather
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/air_quality"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/alert"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/forecast"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/location"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/quota"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/weather"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/schemata"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/cache"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
 	weatherApiConf "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"log"
+	"math"
+	"strings"
+	"sync"
 	"time"
 )
 
+// fetchUnits is the unit system providers are queried in
+const fetchUnits = "metric"
+
+// fetchLanguage is the language providers are asked to describe weather in, descriptions being
+// translated from their condition codes on output
+const fetchLanguage = "en"
+
 type Service struct {
-	db         *gorm.DB
-	repository weather.Repository
+	db            *gorm.DB
+	repository    weather.Repository
+	forecasts     forecast.Repository
+	airQualities  air_quality.Repository
+	alerts        alert.Repository
+	locations     location.Repository
+	providers     *weather_api.Registry
+	providerNames []weather_api.WeatherProvider
+	config        weatherApiConf.Config
+	cache         *cache.Cache[models.Weather]
+	reverse       *cache.Cache[uuid.UUID]
+	searches      *cache.Cache[[]weatherApiSchemata.Place]
+	fetchedAlerts *cache.Cache[[]models.Alert]
+	// fetchedForecasts only coalesces concurrent forecast fetches, the stored forecasts being served until they expire
+	fetchedForecasts *cache.Cache[models.Forecast]
 }
 
 func NewService(db *gorm.DB) Service {
+	conf := weatherApiConf.LoadFromEnv()
+
+	providerNames := make([]weather_api.WeatherProvider, 0, len(conf.Providers))
+	for _, name := range conf.Providers {
+		providerNames = append(providerNames, weather_api.WeatherProvider(strings.TrimSpace(name)))
+	}
+
 	return Service{
-		db:         db,
-		repository: weather.NewRepository(db),
+		db:               db,
+		repository:       weather.NewRepository(db),
+		forecasts:        forecast.NewRepository(db),
+		airQualities:     air_quality.NewRepository(db),
+		alerts:           alert.NewRepository(db),
+		locations:        location.NewRepository(db),
+		providers:        weather_api.LoadRegistry(conf, quota.NewRepository(db)),
+		providerNames:    providerNames,
+		config:           conf,
+		cache:            cache.New[models.Weather](conf.CacheTTL),
+		reverse:          cache.New[uuid.UUID](conf.ReverseGeocodingTTL),
+		searches:         cache.New[[]weatherApiSchemata.Place](conf.SearchTTL),
+		fetchedAlerts:    cache.New[[]models.Alert](conf.AlertsTTL),
+		fetchedForecasts: cache.New[models.Forecast](0),
 	}
 }
 
-func (s Service) paginatedList(ctx context.Context, page int) (*ListOutput, error) {
-	if page == 0 {
-		page = 1
+// paginatedList returns the numbered page of the weathers input asks for, the first when input.Page isn't
+// positive, counting the weathers unless input.Count is false.
+func (s Service) paginatedList(ctx context.Context, input ListInput) (*ListOutput, error) {
+	if input.Page < 1 {
+		input.Page = 1
 	}
 
-	weathers, totalPage, count, err := s.repository.PaginatedList(ctx, page)
+	filter := metricFilter(input)
+
+	weathers, more, err := s.repository.PaginatedList(ctx, input.Page, input.PageSize, filter, input.Sort)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ListOutput{
-		Weathers: weathers,
+	output := &ListOutput{
+		Weathers: presentAll(weathers, input.Presentation),
 		Pagination: schemata.Pagination{
-			TotalPage:   totalPage,
-			TotalCount:  count,
-			CurrentPage: page,
+			CurrentPage: input.Page,
+			PageSize:    input.PageSize,
 		},
+		Units: unitsOf(input.Units),
+	}
+
+	if input.Count == nil || *input.Count {
+		count, err := s.repository.Count(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		totalPage := totalPages(count, input.PageSize)
+		output.Pagination.TotalCount, output.Pagination.TotalPage = &count, &totalPage
+	}
+
+	// numbered pages of the newest first order lead on to cursor pages
+	if len(input.Sort) == 0 && len(weathers) > 0 {
+		first, last := weathers[0], weathers[len(weathers)-1]
+
+		if more {
+			output.Next = weather.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.String()
+		}
+		if input.Page > 1 {
+			output.Prev = weather.Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Before: true}.String()
+		}
+	}
+
+	return output, nil
+}
+
+// keysetList returns the page of the weathers next to the cursor of input, counting the weathers when
+// input.Count is true. It returns weather.InvalidCursorErr when the cursor can't be decoded.
+func (s Service) keysetList(ctx context.Context, input ListInput) (*CursorListOutput, error) {
+	cursor, err := weather.ParseCursor(input.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := metricFilter(input)
+
+	page, err := s.repository.KeysetList(ctx, input.PageSize, filter, &cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &CursorListOutput{
+		Weathers:   presentAll(page.Weathers, input.Presentation),
+		Pagination: schemata.CursorPagination{PageSize: input.PageSize},
+		Units:      unitsOf(input.Units),
+	}
+
+	if page.Next != nil {
+		output.Next = page.Next.String()
+	}
+	if page.Prev != nil {
+		output.Prev = page.Prev.String()
+	}
+
+	if input.Count != nil && *input.Count {
+		count, err := s.repository.Count(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		totalPage := totalPages(count, input.PageSize)
+		output.Pagination.TotalCount, output.Pagination.TotalPage = &count, &totalPage
+	}
+
+	return output, nil
+}
+
+// totalPages returns how many pages of pageSize weathers count weathers take.
+func totalPages(count int64, pageSize int) int64 {
+	return int64(math.Ceil(float64(count) / float64(pageSize)))
+}
+
+// metricFilter returns the filter of input with its ranges converted from input's units to the stored ones.
+func metricFilter(input ListInput) schemata.ListFilter {
+	filter := input.Filter
+	filter.MinTemperature = convertOptional(filter.MinTemperature, input.Units, temperatureToMetric)
+	filter.MaxTemperature = convertOptional(filter.MaxTemperature, input.Units, temperatureToMetric)
+	filter.MinWindSpeed = convertOptional(filter.MinWindSpeed, input.Units, windSpeedToMetric)
+	filter.MaxWindSpeed = convertOptional(filter.MaxWindSpeed, input.Units, windSpeedToMetric)
+
+	return filter
+}
+
+// providerStatuses returns the status of every registered provider, health checking them all at once when
+// check is set, each within the provider timeout.
+func (s Service) providerStatuses(ctx context.Context, check bool) []ProviderStatusOutput {
+	positions := make(map[weather_api.WeatherProvider]int, len(s.providerNames))
+	for i, name := range s.providerNames {
+		positions[name] = i + 1
+	}
+
+	names := s.providers.Names()
+	output := make([]ProviderStatusOutput, 0, len(names))
+	for _, name := range names {
+		provider, err := s.providers.Get(name)
+		if err != nil {
+			continue
+		}
+
+		output = append(output, mapProviderToStatusOutput(provider, positions[name], s.providers.Breaker(name).State()))
+	}
+
+	if check {
+		var wg sync.WaitGroup
+		for i := range output {
+			provider, _ := s.providers.Get(weather_api.WeatherProvider(output[i].Name))
+
+			wg.Add(1)
+			go func(status *ProviderStatusOutput) {
+				defer wg.Done()
+
+				checkCtx := ctx
+				if s.config.ProviderTimeout > 0 {
+					var cancel context.CancelFunc
+					checkCtx, cancel = context.WithTimeout(ctx, s.config.ProviderTimeout)
+					defer cancel()
+				}
+
+				err := provider.HealthCheck(checkCtx)
+				healthy := err == nil
+				status.Healthy = &healthy
+				if err != nil {
+					status.HealthError = err.Error()
+				}
+			}(&output[i])
+		}
+		wg.Wait()
+	}
+
+	return output
+}
+
+func (s Service) providerQuotas(ctx context.Context) ([]ProviderQuotaOutput, error) {
+	names := s.providers.Names()
+	output := make([]ProviderQuotaOutput, 0, len(names))
+
+	for _, name := range names {
+		limiter := s.providers.Limiter(name)
+		if limiter == nil {
+			continue
+		}
+
+		status, err := limiter.Status(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, mapQuotaStatusToQuotaOutput(string(name), status))
+	}
+
+	return output, nil
+}
+
+// latestByCityName returns the latest weather stored for cityName. When it is older than
+// input.MaxAge it is returned flagged as stale and refreshed in background, or refreshed
+// before answering in strict mode.
+func (s Service) latestByCityName(ctx context.Context, cityName string, input LatestInput) (*LatestOutput, error) {
+	w, err := s.latestWeather(ctx, cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	units := unitsOf(input.Units)
+
+	if input.MaxAge == nil || time.Since(w.FetchedAt) <= *input.MaxAge {
+		return &LatestOutput{Weather: present(*w, input.Presentation), Units: units}, nil
+	}
+
+	refreshInput := FetchDataInput{CityName: w.CityName, Country: w.Country}
+
+	if !input.Strict {
+		go s.refresh(context.WithoutCancel(ctx), refreshInput)
+
+		return &LatestOutput{Weather: present(*w, input.Presentation), Stale: true, Units: units}, nil
+	}
+
+	fresh, _, err := s.fetchData(ctx, refreshInput)
+	if err != nil {
+		return nil, err
+	}
+
+	// a fetch cached for longer than MaxAge is still stale
+	return &LatestOutput{
+		Weather: present(*fresh, input.Presentation),
+		Stale:   time.Since(fresh.FetchedAt) > *input.MaxAge,
+		Units:   units,
 	}, nil
 }
 
-func (s Service) latestByCityName(ctx context.Context, cityName string) (*models.Weather, error) {
-	w, err := s.repository.LatestByCityName(ctx, cityName)
+// refresh fetches and stores the weather of input. Concurrent refreshes of the same
+// location share a single provider call through the fetch cache.
+func (s Service) refresh(ctx context.Context, input FetchDataInput) {
+	_, _, err := s.fetchData(ctx, input)
+	if err != nil {
+		log.Printf("could not refresh weather of %s: %v", input.CityName, err)
+	}
+}
+
+// forecastByCityName returns the forecast stored for the location known by cityName, fetching and storing
+// a new one through the providers able to forecast when there is none or it has expired, as presentation
+// asks for. Forecasts are stored by location, so that every name of a city shares them, or by cityName
+// when it can't be resolved.
+func (s Service) forecastByCityName(ctx context.Context, cityName, country string, presentation Presentation) (*ForecastOutput, error) {
+	f, err := s.findForecast(ctx, cityName, country)
 	if err != nil {
 		return nil, err
 	}
 
-	return w, nil
+	return &ForecastOutput{Forecast: presentForecast(*f, presentation), Units: unitsOf(presentation.Units)}, nil
+}
+
+// findForecast returns the stored forecast forecastByCityName presents. Concurrent fetches of the
+// forecast of the same location share a single provider call.
+func (s Service) findForecast(ctx context.Context, cityName, country string) (*models.Forecast, error) {
+	input := FetchDataInput{CityName: cityName, Country: country}
+
+	l, err := s.locationOf(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := s.storedForecast(ctx, l, cityName, country)
+	if f != nil || err != nil {
+		return f, err
+	}
+
+	key := normalizeName(cityName) + "|" + strings.ToUpper(strings.TrimSpace(country))
+	if l != nil {
+		key = l.ID.String()
+	}
+
+	fresh, _, err := s.fetchedForecasts.Fetch(ctx, key, func(ctx context.Context) (models.Forecast, error) {
+		// a fetch that just ended may have stored it
+		f, err := s.storedForecast(ctx, l, cityName, country)
+		if err == nil && f == nil {
+			f, err = s.fetchForecast(ctx, l, input)
+		}
+		if err != nil {
+			return models.Forecast{}, err
+		}
+
+		return *f, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &fresh, nil
+}
+
+// storedForecast returns the forecast stored for l, or for cityName when l is nil, or nil when there is
+// none or it has expired.
+func (s Service) storedForecast(ctx context.Context, l *models.Location, cityName, country string) (*models.Forecast, error) {
+	var f *models.Forecast
+	var err error
+	if l != nil {
+		f, err = s.forecasts.LatestByLocationID(ctx, l.ID)
+	} else {
+		f, err = s.forecasts.LatestByCityName(ctx, cityName, country)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(f.ExpiresAt) {
+		return nil, nil
+	}
+
+	return f, nil
+}
+
+// fetchForecast returns the forecast of l, or of input when l is nil, fetched through the providers able
+// to forecast, and stored.
+func (s Service) fetchForecast(ctx context.Context, l *models.Location, input FetchDataInput) (*models.Forecast, error) {
+	chain, err := s.providers.Chain(s.providerNames, s.config.ProviderTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	location := mapFetchDataInputToLocation(input)
+	if l != nil {
+		location = mapLocationModelToLocation(*l)
+	}
+
+	response, err := chain.FetchForecast(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := mapFetchForecastResponseToForecastModel(*response, s.config.ForecastTTL)
+	if l != nil {
+		fresh.CityName = l.Name
+		fresh.Country = l.CountryCode
+		fresh.LocationID = &l.ID
+	}
+
+	err = s.forecasts.Create(ctx, &fresh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fresh, nil
 }
 
 func (s Service) findById(ctx context.Context, id uuid.UUID) (*models.Weather, error) {
@@ -62,29 +413,136 @@ func (s Service) findById(ctx context.Context, id uuid.UUID) (*models.Weather, e
 	return w, nil
 }
 
+// weatherById returns the weather with id as presentation asks for.
+func (s Service) weatherById(ctx context.Context, id uuid.UUID, presentation Presentation) (*WeatherOutput, error) {
+	w, err := s.findById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WeatherOutput{Weather: present(*w, presentation), Units: unitsOf(presentation.Units)}, nil
+}
+
 func (s Service) deleteById(ctx context.Context, id uuid.UUID) error {
-	return s.repository.DeleteById(ctx, id)
+	err := s.repository.DeleteById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.forget(id)
+
+	return nil
 }
 
-func (s Service) fetchData(ctx context.Context, input FetchDataInput) (*models.Weather, error) {
-	fetchWeatherFunc, err := weather_api.LoadFetchWeatherByLocationFunc(weather_api.OpenWeather)
+// fetchData returns the weather fetched for input through the providers and stored, or the
+// weather fetched for the same location within the cache TTL, telling which one it was.
+func (s Service) fetchData(ctx context.Context, input FetchDataInput) (*models.Weather, cache.Status, error) {
+	w, status, err := s.cache.Fetch(ctx, s.cacheKey(input), func(ctx context.Context) (models.Weather, error) {
+		w, err := s.fetchAndStore(ctx, input)
+		if err != nil {
+			return models.Weather{}, err
+		}
+
+		return *w, nil
+	})
+	if err != nil {
+		return nil, status, err
+	}
+
+	return &w, status, nil
+}
+
+// cacheKey identifies the provider answer input asks for: the same location asked with any
+// spacing or case, or coordinates equal to about 10 meters, to the same providers in the same mode, units and
+// language.
+func (s Service) cacheKey(input FetchDataInput) string {
+	coordinates := ""
+	if location := mapFetchDataInputToLocation(input); location.Coordinates != nil {
+		coordinates = fmt.Sprintf("%.4f,%.4f", location.Coordinates.Latitude, location.Coordinates.Longitude)
+	}
+
+	providers := make([]string, len(s.providerNames))
+	for i, name := range s.providerNames {
+		providers[i] = string(name)
+	}
+
+	provider := strings.Join(providers, ",")
+	if input.Mode == FetchModeConsensus {
+		provider = weather_api.ConsensusProvider + ":" + provider
+	}
+
+	locationID := ""
+	if input.LocationID != nil {
+		locationID = input.LocationID.String()
+	}
+
+	return strings.Join([]string{
+		locationID,
+		normalizeName(input.CityName),
+		strings.ToUpper(strings.TrimSpace(input.Country)),
+		coordinates,
+		provider,
+		fetchUnits,
+		fetchLanguage,
+	}, "|")
+}
+
+// forget drops the cached fetches of the weather with id so it isn't served once changed or deleted.
+func (s Service) forget(id uuid.UUID) {
+	s.cache.DeleteFunc(func(w models.Weather) bool {
+		return w.ID == id
+	})
+}
+
+func (s Service) fetchAndStore(ctx context.Context, input FetchDataInput) (*models.Weather, error) {
+	chain, err := s.providers.Chain(s.providerNames, s.config.ProviderTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	conf := weatherApiConf.LoadFromEnv()
-	fetchWeatherResponse, err := fetchWeatherFunc(ctx, input.CityName, input.Country, conf)
+	l, err := s.locationOf(ctx, input)
 	if err != nil {
 		return nil, err
 	}
 
-	w := mapFetchWeatherResponseToWeatherModel(*fetchWeatherResponse)
+	location := mapFetchDataInputToLocation(input)
+	if l != nil {
+		location = mapLocationModelToLocation(*l)
+	}
+
+	var w models.Weather
+
+	switch input.Mode {
+	case FetchModeConsensus:
+		consensusResponse, err := chain.FetchConsensus(ctx, location, s.config.Consensus)
+		if err != nil {
+			return nil, err
+		}
+
+		w = mapConsensusResponseToWeatherModel(*consensusResponse)
+	default:
+		fetchWeatherResponse, err := chain.FetchCurrent(ctx, location)
+		if err != nil {
+			return nil, err
+		}
+
+		w = mapFetchWeatherResponseToWeatherModel(*fetchWeatherResponse)
+	}
+
+	// providers may name the station nearest to the coordinates rather than the location asked for
+	if l != nil {
+		w.CityName = l.Name
+		w.Country = l.CountryCode
+		w.LocationID = &l.ID
+	}
 
 	err = s.repository.Create(ctx, &w)
 	if err != nil {
 		return nil, err
 	}
 
+	w.Location = l
+
 	return &w, nil
 }
 
@@ -97,11 +555,21 @@ func (s Service) update(ctx context.Context, id uuid.UUID, input UpdateInput) (*
 		return nil, err
 	}
 
+	// an edited description is no longer the one of the stored condition, so it mustn't be translated
+	if input.Description != nil {
+		repoInput["condition_code"] = nil
+	}
+
+	// edited records are left alone by remapping
+	repoInput["edited_at"] = now
+
 	err = s.repository.Update(ctx, id, repoInput)
 	if err != nil {
 		return nil, err
 	}
 
+	s.forget(id)
+
 	w, err := s.findById(ctx, id)
 	if err != nil {
 		return nil, err
@@ -4,14 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	weatherApiConf "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_weather"
 
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/quota"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/weather"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/schemata"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/cache"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/validation"
 	weatherApiSchemata "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -26,7 +34,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 
 	// Auto migrate the schema
-	err = db.AutoMigrate(&models.Weather{})
+	err = db.AutoMigrate(&models.Weather{}, &models.WeatherReading{}, &models.ProviderQuota{}, &models.Forecast{}, &models.Location{}, &models.AirQuality{}, &models.Alert{})
 	require.NoError(t, err)
 
 	return db
@@ -76,6 +84,18 @@ func setupWeatherAPIServer(t *testing.T, mockResponse *weatherApiSchemata.FetchW
 	return server
 }
 
+// setupGeocodingAPIServer answers OpenWeather geocoding requests with places, none meaning the place is unknown.
+func setupGeocodingAPIServer(t *testing.T, places []open_weather.GeocodingResult) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if places == nil {
+			places = []open_weather.GeocodingResult{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(places))
+	}))
+}
+
 func TestService_paginatedList(t *testing.T) {
 	db := setupTestDB(t)
 	service := NewService(db)
@@ -121,7 +141,7 @@ func TestService_paginatedList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := service.paginatedList(context.Background(), tt.page)
+			result, err := service.paginatedList(context.Background(), ListInput{Page: tt.page, PageSize: 10})
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -132,12 +152,157 @@ func TestService_paginatedList(t *testing.T) {
 				assert.NotNil(t, result)
 				assert.Len(t, result.Weathers, tt.expectedCount)
 				assert.Equal(t, tt.page, result.Pagination.CurrentPage)
-				assert.Equal(t, int64(3), result.Pagination.TotalCount)
+				require.NotNil(t, result.Pagination.TotalCount)
+				assert.Equal(t, int64(3), *result.Pagination.TotalCount)
 			}
 		})
 	}
 }
 
+func TestService_paginatedList_Filter(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	testWeathers := []models.Weather{
+		{CityName: "London", Country: "GB", Temperature: 20, WindSpeed: 4, FetchedAt: time.Now()},
+		{CityName: "Paris", Country: "FR", Temperature: 25, WindSpeed: 8, FetchedAt: time.Now()},
+		{CityName: "Berlin", Country: "DE", Temperature: 15, WindSpeed: 2, FetchedAt: time.Now()},
+	}
+	for _, w := range testWeathers {
+		require.NoError(t, db.Create(&w).Error)
+	}
+
+	// 68°F is 20°C and 10 mph about 4.47 m/s
+	minTemperature, maxWindSpeed := 68.0, 10.0
+	result, err := service.paginatedList(context.Background(), ListInput{
+		Page:         1,
+		PageSize:     10,
+		Filter:       schemata.ListFilter{MinTemperature: &minTemperature, MaxWindSpeed: &maxWindSpeed},
+		Sort:         []schemata.SortField{{Field: "temperature", Desc: true}},
+		Presentation: Presentation{Units: UnitsImperial},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Weathers, 1)
+	assert.Equal(t, "London", result.Weathers[0].CityName)
+	assert.Equal(t, 68.0, result.Weathers[0].Temperature)
+	require.NotNil(t, result.Pagination.TotalCount)
+	assert.Equal(t, int64(1), *result.Pagination.TotalCount)
+}
+
+func TestService_paginatedList_Cursor(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	now := time.Now()
+	for i := 0; i < 12; i++ {
+		w := models.Weather{CityName: fmt.Sprintf("City%02d", i), Country: "NO", FetchedAt: now, CreatedAt: now.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, db.Create(&w).Error)
+	}
+
+	first, err := service.paginatedList(context.Background(), ListInput{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+
+	t.Run("numbered pages lead on to cursor pages", func(t *testing.T) {
+		assert.Len(t, first.Weathers, 10)
+		assert.NotEmpty(t, first.Next)
+		assert.Empty(t, first.Prev)
+	})
+
+	t.Run("cursor pages aren't counted unless asked", func(t *testing.T) {
+		next, err := service.keysetList(context.Background(), ListInput{Cursor: first.Next, PageSize: 10})
+
+		require.NoError(t, err)
+		require.Len(t, next.Weathers, 2)
+		assert.Equal(t, "City01", next.Weathers[0].CityName)
+		assert.Empty(t, next.Next)
+		assert.NotEmpty(t, next.Prev)
+		assert.Nil(t, next.Pagination.TotalCount)
+
+		count := true
+		counted, err := service.keysetList(context.Background(), ListInput{Cursor: first.Next, PageSize: 10, Count: &count})
+
+		require.NoError(t, err)
+		require.NotNil(t, counted.Pagination.TotalCount)
+		assert.Equal(t, int64(12), *counted.Pagination.TotalCount)
+		assert.Equal(t, int64(2), *counted.Pagination.TotalPage)
+	})
+
+	t.Run("numbered pages aren't counted when asked not to be", func(t *testing.T) {
+		count := false
+		uncounted, err := service.paginatedList(context.Background(), ListInput{Page: 1, PageSize: 10, Count: &count})
+
+		require.NoError(t, err)
+		assert.Len(t, uncounted.Weathers, 10)
+		assert.Nil(t, uncounted.Pagination.TotalCount)
+		assert.Nil(t, uncounted.Pagination.TotalPage)
+		assert.Equal(t, first.Next, uncounted.Next)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := service.keysetList(context.Background(), ListInput{Cursor: "not-base64!"})
+
+		assert.ErrorIs(t, err, weather.InvalidCursorErr)
+	})
+
+	t.Run("sorted pages have no cursors", func(t *testing.T) {
+		sorted, err := service.paginatedList(context.Background(), ListInput{Page: 1, PageSize: 10, Sort: []schemata.SortField{{Field: "city_name"}}})
+
+		require.NoError(t, err)
+		assert.Empty(t, sorted.Next)
+	})
+}
+
+func TestService_paginatedList_PageSize(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		w := models.Weather{CityName: fmt.Sprintf("City%02d", i), Country: "NO", FetchedAt: now, CreatedAt: now.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, db.Create(&w).Error)
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		result, err := service.paginatedList(context.Background(), ListInput{Page: 1, PageSize: 4})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Weathers, 4)
+		assert.Equal(t, 4, result.Pagination.PageSize)
+		require.NotNil(t, result.Pagination.TotalPage)
+		assert.Equal(t, int64(3), *result.Pagination.TotalPage)
+	})
+
+	t.Run("requested page size", func(t *testing.T) {
+		result, err := service.paginatedList(context.Background(), ListInput{Page: 2, PageSize: 6})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Weathers, 4)
+		assert.Equal(t, 6, result.Pagination.PageSize)
+		require.NotNil(t, result.Pagination.TotalPage)
+		assert.Equal(t, int64(2), *result.Pagination.TotalPage)
+		assert.Empty(t, result.Next)
+
+		count := true
+		next, err := service.keysetList(context.Background(), ListInput{Cursor: result.Prev, PageSize: 6, Count: &count})
+
+		require.NoError(t, err)
+		assert.Len(t, next.Weathers, 6)
+		assert.Equal(t, "City09", next.Weathers[0].CityName)
+		assert.Equal(t, 6, next.Pagination.PageSize)
+		assert.Equal(t, int64(2), *next.Pagination.TotalPage)
+	})
+
+	t.Run("out of range page", func(t *testing.T) {
+		result, err := service.paginatedList(context.Background(), ListInput{Page: -1, PageSize: 4})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Pagination.CurrentPage)
+		assert.Equal(t, 4, result.Pagination.PageSize)
+		assert.Equal(t, "City09", result.Weathers[0].CityName)
+	})
+}
+
 func TestService_latestByCityName(t *testing.T) {
 	db := setupTestDB(t)
 	service := NewService(db)
@@ -188,7 +353,7 @@ func TestService_latestByCityName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := service.latestByCityName(context.Background(), tt.cityName)
+			result, err := service.latestByCityName(context.Background(), tt.cityName, LatestInput{})
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -374,6 +539,7 @@ func TestService_update(t *testing.T) {
 				assert.Equal(t, tt.expectedResult.CityName, result.CityName)
 				assert.Equal(t, tt.expectedResult.Temperature, result.Temperature)
 				assert.Equal(t, tt.expectedResult.Country, result.Country)
+				assert.NotNil(t, result.EditedAt, "edited records are flagged")
 			}
 		})
 	}
@@ -396,6 +562,7 @@ func TestService_fetchData(t *testing.T) {
 		name           string
 		input          FetchDataInput
 		mockResponse   *weatherApiSchemata.FetchWeatherResponse
+		places         []open_weather.GeocodingResult
 		statusCode     int
 		expectedResult *models.Weather
 		expectedError  error
@@ -406,6 +573,7 @@ func TestService_fetchData(t *testing.T) {
 				CityName: "London",
 				Country:  "UK",
 			},
+			places:       []open_weather.GeocodingResult{{Name: "London", Country: "UK", Lat: 51.5074, Lon: -0.1278}},
 			mockResponse: mockResponse,
 			statusCode:   http.StatusOK,
 			expectedResult: &models.Weather{
@@ -435,6 +603,7 @@ func TestService_fetchData(t *testing.T) {
 				CityName: "ServerError",
 				Country:  "Error",
 			},
+			places:         []open_weather.GeocodingResult{{Name: "ServerError", Country: "Error"}},
 			mockResponse:   nil,
 			statusCode:     http.StatusInternalServerError,
 			expectedResult: nil,
@@ -452,7 +621,14 @@ func TestService_fetchData(t *testing.T) {
 			open_weather.SetBaseURL(server.URL)
 			defer open_weather.SetBaseURL(originalBaseURL)
 
-			result, err := service.fetchData(context.Background(), tt.input)
+			geocodingServer := setupGeocodingAPIServer(t, tt.places)
+			defer geocodingServer.Close()
+
+			originalGeocodingBaseURL := open_weather.GetGeocodingBaseURL()
+			open_weather.SetGeocodingBaseURL(geocodingServer.URL)
+			defer open_weather.SetGeocodingBaseURL(originalGeocodingBaseURL)
+
+			result, _, err := service.fetchData(context.Background(), tt.input)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -473,3 +649,967 @@ func TestService_fetchData(t *testing.T) {
 		})
 	}
 }
+
+type fakeProvider struct {
+	name      string
+	response  *weatherApiSchemata.FetchWeatherResponse
+	err       error
+	transient bool
+	calls     *int
+	location  *weatherApiSchemata.Location
+}
+
+func (p fakeProvider) Name() string {
+	return p.name
+}
+
+func (p fakeProvider) FetchCurrent(ctx context.Context, location weatherApiSchemata.Location) (*weatherApiSchemata.FetchWeatherResponse, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+	if p.location != nil {
+		*p.location = location
+	}
+
+	return p.response, p.err
+}
+
+func (p fakeProvider) HealthCheck(ctx context.Context) error {
+	return p.err
+}
+
+func (p fakeProvider) IsTransient(err error) bool {
+	return p.transient
+}
+
+type fakeForecaster struct {
+	fakeProvider
+	forecast *weatherApiSchemata.FetchForecastResponse
+}
+
+func (p fakeForecaster) FetchForecast(ctx context.Context, location weatherApiSchemata.Location) (*weatherApiSchemata.FetchForecastResponse, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+
+	return p.forecast, p.err
+}
+
+type fakeGeocoder struct {
+	fakeProvider
+	places       []weatherApiSchemata.Place
+	geocodeCalls *int
+}
+
+func (p fakeGeocoder) Geocode(ctx context.Context, location weatherApiSchemata.Location) ([]weatherApiSchemata.Place, error) {
+	if p.geocodeCalls != nil {
+		*p.geocodeCalls++
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.places, nil
+}
+
+type fakeReverseGeocoder struct {
+	fakeProvider
+	places []weatherApiSchemata.Place
+}
+
+func (p fakeReverseGeocoder) ReverseGeocode(ctx context.Context, coordinates weatherApiSchemata.Coordinates) ([]weatherApiSchemata.Place, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.places, nil
+}
+
+func TestService_fetchData_UsesConfiguredProvider(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Fake", response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin",
+		Country:      "DE",
+		Temperature:  12.5,
+	}})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"Unknown"}
+
+		result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin"})
+
+		assert.ErrorIs(t, err, weather_api.ProviderNotImplementedErr)
+		assert.Nil(t, result)
+	})
+
+	t.Run("registered provider", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"Fake"}
+
+		result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Berlin", result.CityName)
+		assert.Equal(t, 12.5, result.Temperature)
+	})
+}
+
+func TestService_fetchData_FallbackChain(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Down", err: errors.New("connection refused"), transient: true})
+	service.providers.Register(fakeProvider{name: "Missing", err: errors.New("not-found"), transient: false})
+	service.providers.Register(fakeProvider{name: "Up", response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin",
+		Country:      "DE",
+	}})
+
+	t.Run("falls through transient failures and records the serving provider", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"Down", "Up"}
+
+		result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Up", result.Provider)
+
+		var stored models.Weather
+		require.NoError(t, db.First(&stored, "id = ?", result.ID).Error)
+		assert.Equal(t, "Up", stored.Provider)
+	})
+
+	t.Run("stops on definitive answers", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"Missing", "Up"}
+
+		result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin"})
+
+		assert.EqualError(t, err, "not-found")
+		assert.Nil(t, result)
+	})
+}
+
+func TestService_fetchData_Consensus(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "A", response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin", Country: "DE", Temperature: 10, Humidity: 60, WindSpeed: 2, Description: "clear sky",
+	}})
+	service.providers.Register(fakeProvider{name: "B", response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin", Country: "DE", Temperature: 20, Humidity: 62, WindSpeed: 3, Description: "clear sky",
+	}})
+	service.providerNames = []weather_api.WeatherProvider{"A", "B"}
+
+	result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin", Mode: FetchModeConsensus})
+
+	require.NoError(t, err)
+	assert.Equal(t, weather_api.ConsensusProvider, result.Provider)
+	assert.Equal(t, 15.0, result.Temperature)
+	assert.Equal(t, []string{"temperature"}, result.Disagreements)
+
+	stored, err := service.findById(context.Background(), result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"temperature"}, stored.Disagreements)
+	require.Len(t, stored.Readings, 2)
+
+	providers := []string{stored.Readings[0].Provider, stored.Readings[1].Provider}
+	assert.ElementsMatch(t, []string{"A", "B"}, providers)
+}
+
+func TestService_providerStatuses(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute}, nil)
+	service.providers.Register(fakeProvider{name: "A", err: errors.New("server error"), transient: true})
+	service.providers.Register(fakeProvider{name: "B"})
+	service.providerNames = []weather_api.WeatherProvider{"A"}
+
+	_, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin"})
+	assert.ErrorIs(t, err, weather_api.ProvidersExhaustedErr)
+
+	output := service.providerStatuses(context.Background(), false)
+
+	assert.Equal(t, []ProviderStatusOutput{
+		{Name: "A", Capabilities: []string{"current-weather"}, Position: 1, CircuitState: "open"},
+		{Name: "B", Capabilities: []string{"current-weather"}, Position: 0, CircuitState: "closed"},
+	}, output)
+
+	healthy, unhealthy := true, false
+	checked := service.providerStatuses(context.Background(), true)
+
+	assert.Equal(t, []ProviderStatusOutput{
+		{Name: "A", Capabilities: []string{"current-weather"}, Position: 1, CircuitState: "open", Healthy: &unhealthy, HealthError: "server error"},
+		{Name: "B", Capabilities: []string{"current-weather"}, Position: 0, CircuitState: "closed", Healthy: &healthy},
+	}, checked)
+
+	_, _, err = service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin"})
+	assert.ErrorIs(t, err, weather_api.CircuitOpenErr)
+}
+
+func TestService_providerQuotas(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	server := setupWeatherAPIServer(t, &weatherApiSchemata.FetchWeatherResponse{LocationName: "London", Country: "GB"}, http.StatusOK)
+	defer server.Close()
+
+	originalBaseURL := open_weather.GetBaseURL()
+	open_weather.SetBaseURL(server.URL)
+	defer open_weather.SetBaseURL(originalBaseURL)
+
+	geocodingServer := setupGeocodingAPIServer(t, []open_weather.GeocodingResult{{Name: "London", Country: "GB", Lat: 51.5074, Lon: -0.1278}})
+	defer geocodingServer.Close()
+
+	originalGeocodingBaseURL := open_weather.GetGeocodingBaseURL()
+	open_weather.SetGeocodingBaseURL(geocodingServer.URL)
+	defer open_weather.SetGeocodingBaseURL(originalGeocodingBaseURL)
+
+	config := weatherApiConf.Config{}
+	config.OpenWeather.RateLimit.DailyQuota = 10
+	service.providers = weather_api.NewRegistry(config.CircuitBreaker, quota.NewRepository(db))
+	service.providers.Register(open_weather.NewProvider(config))
+	service.providerNames = []weather_api.WeatherProvider{weather_api.OpenWeather}
+
+	_, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "London"})
+	require.NoError(t, err)
+
+	output, err := service.providerQuotas(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ProviderQuotaOutput{
+		// geocoding London counts too
+		{Name: "OpenWeather", DailyQuota: 10, UsedToday: 2, RemainingToday: 8},
+	}, output)
+}
+
+func TestService_fetchData_Cache(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	calls := 0
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Fake", calls: &calls, response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin",
+		Country:      "DE",
+	}})
+	service.providerNames = []weather_api.WeatherProvider{"Fake"}
+	service.cache = cache.New[models.Weather](time.Minute)
+
+	first, status, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin", Country: "DE"})
+	require.NoError(t, err)
+	assert.Equal(t, cache.Miss, status)
+
+	t.Run("serves the same location from the cache", func(t *testing.T) {
+		result, status, err := service.fetchData(context.Background(), FetchDataInput{CityName: "  berlin ", Country: "de"})
+
+		require.NoError(t, err)
+		assert.Equal(t, cache.Hit, status)
+		assert.Equal(t, first.ID, result.ID)
+		assert.Equal(t, 1, calls)
+
+		var count int64
+		db.Model(&models.Weather{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("does not share entries across modes", func(t *testing.T) {
+		_, status, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin", Country: "DE", Mode: FetchModeConsensus})
+
+		require.NoError(t, err)
+		assert.Equal(t, cache.Miss, status)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("forgets deleted weathers", func(t *testing.T) {
+		require.NoError(t, service.deleteById(context.Background(), first.ID))
+
+		result, status, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin", Country: "DE"})
+
+		require.NoError(t, err)
+		assert.Equal(t, cache.Miss, status)
+		assert.NotEqual(t, first.ID, result.ID)
+	})
+}
+
+func TestService_latestByCityName_MaxAge(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	// every connection to an in-memory database opens an empty one, keep the refresh on ours
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	calls := 0
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Fake", calls: &calls, response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin",
+		Country:      "DE",
+		Temperature:  25,
+	}})
+	service.providerNames = []weather_api.WeatherProvider{"Fake"}
+	service.cache = cache.New[models.Weather](0)
+
+	old := models.Weather{CityName: "Berlin", Country: "DE", Temperature: 10, FetchedAt: time.Now().Add(-time.Hour), CreatedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, db.Create(&old).Error)
+
+	maxAge := 10 * time.Minute
+
+	t.Run("fresh enough", func(t *testing.T) {
+		hour := 2 * time.Hour
+		result, err := service.latestByCityName(context.Background(), "Berlin", LatestInput{MaxAge: &hour})
+
+		require.NoError(t, err)
+		assert.Equal(t, old.ID, result.ID)
+		assert.False(t, result.Stale)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("strict mode fetches before answering", func(t *testing.T) {
+		result, err := service.latestByCityName(context.Background(), "Berlin", LatestInput{MaxAge: &maxAge, Strict: true})
+
+		require.NoError(t, err)
+		assert.NotEqual(t, old.ID, result.ID)
+		assert.Equal(t, 25.0, result.Temperature)
+		assert.False(t, result.Stale)
+		assert.Equal(t, 1, calls)
+
+		require.NoError(t, db.Delete(&models.Weather{}, "id = ?", result.ID).Error)
+	})
+
+	t.Run("serves stale weather and refreshes it in background", func(t *testing.T) {
+		result, err := service.latestByCityName(context.Background(), "Berlin", LatestInput{MaxAge: &maxAge})
+
+		require.NoError(t, err)
+		assert.Equal(t, old.ID, result.ID)
+		assert.True(t, result.Stale)
+
+		assert.Eventually(t, func() bool {
+			latest, err := service.repository.LatestByCityName(context.Background(), "Berlin")
+			return err == nil && latest.ID != old.ID
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestService_forecastByCityName(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+	service.config.ForecastTTL = time.Hour
+
+	calls := 0
+	entryTime := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Current"})
+	service.providers.Register(fakeForecaster{
+		fakeProvider: fakeProvider{name: "Forecast", calls: &calls},
+		forecast: &weatherApiSchemata.FetchForecastResponse{
+			LocationName: "London",
+			Country:      "GB",
+			Entries:      []weatherApiSchemata.ForecastEntry{{Time: entryTime, Temperature: 18, Description: "light rain"}},
+		},
+	})
+	service.providerNames = []weather_api.WeatherProvider{"Current", "Forecast"}
+
+	first, err := service.forecastByCityName(context.Background(), "London", "", Presentation{})
+
+	t.Run("fetches and stores a missing forecast", func(t *testing.T) {
+		require.NoError(t, err)
+		assert.Equal(t, "Forecast", first.Provider)
+		assert.Equal(t, []models.ForecastEntry{{Time: entryTime, Temperature: 18, Description: "light rain"}}, first.Entries)
+		assert.WithinDuration(t, first.FetchedAt.Add(time.Hour), first.ExpiresAt, time.Second)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("serves the stored forecast until it expires", func(t *testing.T) {
+		result, err := service.forecastByCityName(context.Background(), "london", "", Presentation{})
+
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, result.ID)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("fetches an expired forecast again", func(t *testing.T) {
+		require.NoError(t, db.Model(&models.Forecast{}).Where("id = ?", first.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		result, err := service.forecastByCityName(context.Background(), "London", "", Presentation{})
+
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, result.ID)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("no provider able to forecast", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"Current"}
+
+		result, err := service.forecastByCityName(context.Background(), "Paris", "", Presentation{})
+
+		assert.ErrorIs(t, err, weather_api.CapabilityNotSupportedErr)
+		assert.Nil(t, result)
+	})
+
+	t.Run("every name of a location shares its forecast", func(t *testing.T) {
+		service.providers.Register(fakeGeocoder{
+			fakeProvider: fakeProvider{name: "Geocoder"},
+			places:       []weatherApiSchemata.Place{{Name: "Lisbon", Country: "PT", Latitude: 38.7223, Longitude: -9.1393}},
+		})
+		service.providerNames = []weather_api.WeatherProvider{"Geocoder", "Forecast"}
+		calls = 0
+
+		first, err := service.forecastByCityName(context.Background(), "Lisbon", "PT", Presentation{})
+		require.NoError(t, err)
+		require.NotNil(t, first.LocationID)
+		assert.Equal(t, "Lisbon", first.CityName)
+
+		result, err := service.forecastByCityName(context.Background(), "Lisboa", "PT", Presentation{})
+
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, result.ID)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestService_fetchData_Coordinates(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	var location weatherApiSchemata.Location
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "Fake", location: &location, response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Springfield",
+		Country:      "US",
+		Coordinates:  &weatherApiSchemata.Coordinates{Latitude: 37.2153, Longitude: -93.2982},
+	}})
+	service.providerNames = []weather_api.WeatherProvider{"Fake"}
+
+	latitude, longitude := 37.2153, -93.2982
+	result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Springfield", Latitude: &latitude, Longitude: &longitude})
+
+	require.NoError(t, err)
+	assert.Equal(t, &weatherApiSchemata.Coordinates{Latitude: latitude, Longitude: longitude}, location.Coordinates)
+
+	stored, err := service.findById(context.Background(), result.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Latitude)
+	require.NotNil(t, stored.Longitude)
+	assert.Equal(t, latitude, *stored.Latitude)
+	assert.Equal(t, longitude, *stored.Longitude)
+
+	t.Run("coordinates are part of the cache key", func(t *testing.T) {
+		otherLatitude := 39.7817
+		_, status, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Springfield", Latitude: &otherLatitude, Longitude: &longitude})
+
+		require.NoError(t, err)
+		assert.Equal(t, cache.Miss, status)
+	})
+}
+
+func TestFetchDataInput_Validation(t *testing.T) {
+	latitude, longitude, outOfRange := 37.2153, -93.2982, 91.0
+	locationID := uuid.New()
+
+	tests := []struct {
+		name          string
+		input         FetchDataInput
+		invalidFields []string
+	}{
+		{name: "city", input: FetchDataInput{CityName: "Springfield"}},
+		{name: "coordinates without city", input: FetchDataInput{Latitude: &latitude, Longitude: &longitude}},
+		{name: "location without city", input: FetchDataInput{LocationID: &locationID}},
+		{name: "neither city nor coordinates", input: FetchDataInput{}, invalidFields: []string{"CityName"}},
+		{name: "latitude without longitude", input: FetchDataInput{Latitude: &latitude}, invalidFields: []string{"Longitude"}},
+		{name: "latitude out of range", input: FetchDataInput{Latitude: &outOfRange, Longitude: &longitude}, invalidFields: []string{"Latitude"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validation.ValidateData(tt.input)
+
+			fields := make([]string, 0, len(errs))
+			for field := range errs {
+				fields = append(fields, field)
+			}
+
+			assert.ElementsMatch(t, tt.invalidFields, fields)
+		})
+	}
+}
+
+func TestService_fetchData_Location(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	geocodeCalls := 0
+	var location weatherApiSchemata.Location
+	geocoder := fakeGeocoder{
+		fakeProvider: fakeProvider{name: "Fake", location: &location, response: &weatherApiSchemata.FetchWeatherResponse{
+			LocationName: "City of London",
+			Country:      "GB",
+			Temperature:  18,
+		}},
+		places:       []weatherApiSchemata.Place{{Name: "London", Country: "GB", State: "England", Latitude: 51.5074, Longitude: -0.1278}},
+		geocodeCalls: &geocodeCalls,
+	}
+
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(geocoder)
+	service.providerNames = []weather_api.WeatherProvider{"Fake"}
+
+	result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "London"})
+
+	require.NoError(t, err)
+	require.NotNil(t, result.LocationID)
+	assert.Equal(t, "London", result.CityName)
+	assert.Equal(t, "GB", result.Country)
+	assert.Equal(t, &weatherApiSchemata.Coordinates{Latitude: 51.5074, Longitude: -0.1278}, location.Coordinates)
+	assert.Equal(t, 1, geocodeCalls)
+
+	stored, err := service.findById(context.Background(), result.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Location)
+	assert.Equal(t, "England", stored.Location.State)
+
+	t.Run("other names of a known place share its location", func(t *testing.T) {
+		geocoder.places = []weatherApiSchemata.Place{{Name: "London", Country: "GB", Latitude: 51.5085, Longitude: -0.1257}}
+		service.providers.Register(geocoder)
+
+		other, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Londres"})
+
+		require.NoError(t, err)
+		assert.Equal(t, result.LocationID, other.LocationID)
+		assert.Equal(t, "London", other.CityName)
+
+		var count int64
+		require.NoError(t, db.Model(&models.Location{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("known names and aliases are not geocoded again", func(t *testing.T) {
+		service.cache = cache.New[models.Weather](0)
+		geocodeCalls = 0
+
+		_, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "LONDRES"})
+		require.NoError(t, err)
+		_, _, err = service.fetchData(context.Background(), FetchDataInput{CityName: "london", Country: "gb"})
+		require.NoError(t, err)
+
+		assert.Zero(t, geocodeCalls)
+	})
+
+	t.Run("latest weather is looked up by location", func(t *testing.T) {
+		latest, err := service.latestByCityName(context.Background(), "Londres", LatestInput{})
+
+		require.NoError(t, err)
+		assert.Equal(t, result.LocationID, latest.LocationID)
+		require.NotNil(t, latest.Location)
+		assert.Equal(t, "London", latest.Location.Name)
+	})
+
+	t.Run("unknown places are not fetched", func(t *testing.T) {
+		service.providers.Register(fakeGeocoder{fakeProvider: fakeProvider{name: "Fake", err: errors.New("not-found")}})
+
+		_, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Atlantis"})
+
+		assert.EqualError(t, err, "not-found")
+	})
+
+	t.Run("weather is fetched by name when no provider can geocode", func(t *testing.T) {
+		service.providers.Register(fakeProvider{name: "Fake", location: &location, response: &weatherApiSchemata.FetchWeatherResponse{
+			LocationName: "Paris",
+			Country:      "FR",
+		}})
+
+		w, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Paris"})
+
+		require.NoError(t, err)
+		assert.Nil(t, w.LocationID)
+		assert.Nil(t, location.Coordinates)
+		assert.Equal(t, "Paris", location.CityName)
+	})
+}
+
+func TestService_reverseGeocode(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	calls := 0
+	var location weatherApiSchemata.Location
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeReverseGeocoder{
+		fakeProvider: fakeProvider{name: "Fake", location: &location, response: &weatherApiSchemata.FetchWeatherResponse{
+			LocationName: "Springfield",
+			Country:      "US",
+			Temperature:  24,
+		}},
+		places: []weatherApiSchemata.Place{{Name: "Springfield", Country: "US", State: "Missouri", Latitude: 37.2153, Longitude: -93.2982}},
+	})
+	service.providerNames = []weather_api.WeatherProvider{"Fake"}
+
+	output, status, weatherStatus, err := service.reverseGeocode(context.Background(), ReverseGeocodeInput{Latitude: 37.2089, Longitude: -93.2923})
+
+	require.NoError(t, err)
+	assert.Equal(t, cache.Miss, status)
+	assert.Empty(t, weatherStatus)
+	assert.Equal(t, "Springfield", output.Location.Name)
+	assert.Equal(t, "Missouri", output.Location.State)
+	assert.Nil(t, output.Weather)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Location{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	t.Run("nearby coordinates are served from cache", func(t *testing.T) {
+		service.providers.Register(fakeReverseGeocoder{fakeProvider: fakeProvider{name: "Fake", calls: &calls}})
+
+		cached, status, _, err := service.reverseGeocode(context.Background(), ReverseGeocodeInput{Latitude: 37.2091, Longitude: -93.2921})
+
+		require.NoError(t, err)
+		assert.Equal(t, cache.Hit, status)
+		assert.Equal(t, output.Location.ID, cached.Location.ID)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("cached locations are read again", func(t *testing.T) {
+		require.NoError(t, service.locations.AddAlias(context.Background(), output.Location.ID, "queen city of the ozarks"))
+
+		cached, status, _, err := service.reverseGeocode(context.Background(), ReverseGeocodeInput{Latitude: 37.2091, Longitude: -93.2921})
+
+		require.NoError(t, err)
+		assert.Equal(t, cache.Hit, status)
+		assert.Equal(t, []string{"queen city of the ozarks"}, cached.Location.Aliases)
+	})
+
+	t.Run("weather of the location found", func(t *testing.T) {
+		service.providers.Register(fakeProvider{name: "Fake", location: &location, response: &weatherApiSchemata.FetchWeatherResponse{
+			LocationName: "Springfield",
+			Country:      "US",
+			Temperature:  24,
+		}})
+
+		withWeather, status, weatherStatus, err := service.reverseGeocode(context.Background(), ReverseGeocodeInput{Latitude: 37.2089, Longitude: -93.2923, Weather: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, cache.Hit, status)
+		assert.Equal(t, cache.Miss, weatherStatus)
+		require.NotNil(t, withWeather.Weather)
+		assert.Equal(t, &output.Location.ID, withWeather.Weather.LocationID)
+		assert.Equal(t, 24.0, withWeather.Weather.Temperature)
+		assert.Equal(t, &weatherApiSchemata.Coordinates{Latitude: 37.2153, Longitude: -93.2982}, location.Coordinates)
+	})
+
+	t.Run("no provider able to reverse geocode", func(t *testing.T) {
+		_, _, _, err := service.reverseGeocode(context.Background(), ReverseGeocodeInput{Latitude: 48.85, Longitude: 2.35})
+
+		assert.ErrorIs(t, err, weather_api.CapabilityNotSupportedErr)
+	})
+}
+
+func TestService_searchLocations(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	london := &models.Location{Name: "London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278, Aliases: []string{"londres"}}
+	require.NoError(t, db.Create(london).Error)
+	require.NoError(t, db.Create(&models.Location{Name: "Lyon", CountryCode: "FR", Latitude: 45.764, Longitude: 4.8357}).Error)
+
+	geocodeCalls := 0
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeGeocoder{
+		fakeProvider: fakeProvider{name: "Fake"},
+		places: []weatherApiSchemata.Place{
+			{Name: "London", Country: "GB", State: "England", Latitude: 51.5085, Longitude: -0.1257},
+			{Name: "London", Country: "CA", State: "Ontario", Latitude: 42.9834, Longitude: -81.233},
+			{Name: "Londonderry", Country: "GB", State: "Northern Ireland", Latitude: 54.9966, Longitude: -7.3086},
+		},
+		geocodeCalls: &geocodeCalls,
+	})
+	service.providerNames = []weather_api.WeatherProvider{"Fake"}
+
+	output, err := service.searchLocations(context.Background(), SearchLocationsInput{Query: "Londo", Limit: 10})
+
+	require.NoError(t, err)
+	require.Len(t, output, 3)
+	assert.Equal(t, &london.ID, output[0].LocationID, "known locations win over the places next to them")
+	assert.Equal(t, MatchPrefix, output[0].Match)
+	assert.Equal(t, "CA", output[1].CountryCode)
+	assert.Nil(t, output[1].LocationID)
+	assert.Equal(t, "Londonderry", output[2].Name)
+
+	t.Run("ranks exact before prefix and fuzzy matches", func(t *testing.T) {
+		service.providers.Register(fakeGeocoder{fakeProvider: fakeProvider{name: "Fake"}, places: []weatherApiSchemata.Place{
+			{Name: "Lyons", Country: "US", Latitude: 43.0637, Longitude: -76.9902},
+			{Name: "Lyon", Country: "FR", Latitude: 45.7578, Longitude: 4.832},
+		}})
+
+		output, err := service.searchLocations(context.Background(), SearchLocationsInput{Query: "lyon", Limit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, output, 2)
+		assert.Equal(t, MatchExact, output[0].Match)
+		assert.Equal(t, "FR", output[0].CountryCode)
+		assert.Equal(t, MatchPrefix, output[1].Match)
+	})
+
+	t.Run("misspelled known locations", func(t *testing.T) {
+		service.providers.Register(fakeGeocoder{fakeProvider: fakeProvider{name: "Fake", err: errors.New("not-found")}})
+
+		output, err := service.searchLocations(context.Background(), SearchLocationsInput{Query: "Lodnon", Limit: 10})
+
+		require.Error(t, err, "unknown provider errors are not swallowed")
+
+		service.providers.Register(fakeGeocoder{fakeProvider: fakeProvider{name: "Fake", err: open_weather.NotFoundErr}})
+
+		output, err = service.searchLocations(context.Background(), SearchLocationsInput{Query: "Lodnon", Limit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, output, 1)
+		assert.Equal(t, MatchFuzzy, output[0].Match)
+		assert.Equal(t, "London", output[0].Name)
+	})
+
+	t.Run("known locations alone when no provider can geocode", func(t *testing.T) {
+		service.providers.Register(fakeProvider{name: "Fake"})
+
+		output, err := service.searchLocations(context.Background(), SearchLocationsInput{Query: "londr", Country: "GB", Limit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, output, 1)
+		assert.Equal(t, &london.ID, output[0].LocationID)
+	})
+
+	t.Run("provider searches are cached", func(t *testing.T) {
+		geocodeCalls = 0
+		service.providers.Register(fakeGeocoder{fakeProvider: fakeProvider{name: "Fake"}, geocodeCalls: &geocodeCalls})
+
+		_, err := service.searchLocations(context.Background(), SearchLocationsInput{Query: "Paris", Limit: 10})
+		require.NoError(t, err)
+		_, err = service.searchLocations(context.Background(), SearchLocationsInput{Query: " paris", Limit: 10})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, geocodeCalls)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		output, err := service.searchLocations(context.Background(), SearchLocationsInput{Query: "Londo", Limit: 1})
+
+		require.NoError(t, err)
+		assert.Len(t, output, 1)
+	})
+
+	t.Run("more known locations sharing the first letter than searchCandidates", func(t *testing.T) {
+		service.providers.Register(fakeProvider{name: "Fake"})
+
+		locations := make([]models.Location, searchCandidates+1)
+		for i := range locations {
+			locations[i] = models.Location{Name: fmt.Sprintf("La %03d", i), CountryCode: "FR", Latitude: float64(i), Longitude: 0}
+		}
+		require.NoError(t, db.Create(&locations).Error)
+
+		output, err := service.searchLocations(context.Background(), SearchLocationsInput{Query: "Lyon", Limit: 10})
+
+		require.NoError(t, err)
+		require.NotEmpty(t, output)
+		assert.Equal(t, MatchExact, output[0].Match)
+		assert.Equal(t, "Lyon", output[0].Name)
+
+		output, err = service.searchLocations(context.Background(), SearchLocationsInput{Query: "londres", Limit: 10})
+
+		require.NoError(t, err)
+		require.NotEmpty(t, output)
+		assert.Equal(t, &london.ID, output[0].LocationID, "matched by alias")
+	})
+}
+
+func TestService_rawById(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	service.providers = weather_api.NewRegistry(weatherApiConf.CircuitBreakerConfig{}, nil)
+	service.providers.Register(fakeProvider{name: "A", response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin", Country: "DE", Temperature: 10, Raw: json.RawMessage(`{"temp":10}`),
+	}})
+	service.providers.Register(fakeProvider{name: "B", response: &weatherApiSchemata.FetchWeatherResponse{
+		LocationName: "Berlin", Country: "DE", Temperature: 12, Raw: json.RawMessage(`{"t":12}`),
+	}})
+
+	t.Run("stores the payload the provider answered", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"A"}
+
+		result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin"})
+		require.NoError(t, err)
+
+		output, err := service.rawById(context.Background(), result.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "A", output.Provider)
+		assert.JSONEq(t, `{"temp":10}`, string(output.Payload))
+	})
+
+	t.Run("stores the payload of every reading in consensus mode", func(t *testing.T) {
+		service.providerNames = []weather_api.WeatherProvider{"A", "B"}
+
+		result, _, err := service.fetchData(context.Background(), FetchDataInput{CityName: "Berlin", Mode: FetchModeConsensus})
+		require.NoError(t, err)
+
+		output, err := service.rawById(context.Background(), result.ID)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"provider":"A","payload":{"temp":10}},{"provider":"B","payload":{"t":12}}]`, string(output.Payload))
+	})
+
+	t.Run("record stored without payload", func(t *testing.T) {
+		w := models.Weather{CityName: "Paris", Country: "FR", FetchedAt: time.Now()}
+		require.NoError(t, db.Create(&w).Error)
+
+		output, err := service.rawById(context.Background(), w.ID)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, output)
+	})
+}
+
+func TestService_RemapAll(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	fetchedAt := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	openWeatherPayload := `{"name":"London","sys":{"country":"GB"},"main":{"temp":11,"humidity":80,"pressure":1009,"feels_like":9.5},"wind":{"speed":4,"deg":250}}`
+	openMeteoPayload := `{"latitude":51.5,"longitude":-0.12,"current":{"time":"2025-09-01T12:00","temperature_2m":13,"relative_humidity_2m":70,"wind_speed_10m":3,"weather_code":0,"pressure_msl":1013}}`
+
+	// records stored before the mapper read pressure and feels-like
+	single := models.Weather{
+		CityName: "Londres", Country: "GB", Temperature: 11, Humidity: 80, WindSpeed: 4,
+		Provider: string(weather_api.OpenWeather), FetchedAt: fetchedAt, Raw: models.RawPayload(openWeatherPayload),
+	}
+	require.NoError(t, db.Create(&single).Error)
+
+	payloads, err := json.Marshal([]models.ConsensusPayload{
+		{Provider: "OpenWeather", Payload: json.RawMessage(openWeatherPayload)},
+		{Provider: "OpenMeteo", Payload: json.RawMessage(openMeteoPayload)},
+	})
+	require.NoError(t, err)
+
+	consensus := models.Weather{
+		CityName: "London", Country: "GB", Temperature: 12, Humidity: 75, WindSpeed: 3.5,
+		Provider: weather_api.ConsensusProvider, FetchedAt: fetchedAt, Raw: payloads,
+		Readings: []models.WeatherReading{{Provider: "OpenWeather", Temperature: 11}, {Provider: "OpenMeteo", Temperature: 13}},
+	}
+	require.NoError(t, db.Create(&consensus).Error)
+
+	unknown := models.Weather{CityName: "Berlin", Country: "DE", Provider: "Gone", FetchedAt: fetchedAt, Raw: models.RawPayload(`{}`)}
+	require.NoError(t, db.Create(&unknown).Error)
+
+	withoutPayload := models.Weather{CityName: "Paris", Country: "FR", Provider: "OpenWeather", FetchedAt: fetchedAt}
+	require.NoError(t, db.Create(&withoutPayload).Error)
+
+	editedAt := time.Now()
+	edited := models.Weather{
+		CityName: "London", Country: "GB", Temperature: 15, Humidity: 80, WindSpeed: 4,
+		Provider: string(weather_api.OpenWeather), FetchedAt: fetchedAt, Raw: models.RawPayload(openWeatherPayload), EditedAt: &editedAt,
+	}
+	require.NoError(t, db.Create(&edited).Error)
+
+	var progress []RemapOutput
+	output, err := service.RemapAll(context.Background(), func(p RemapOutput) {
+		progress = append(progress, p)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &RemapOutput{Remapped: 2, Failed: 1}, output)
+	assert.Equal(t, []RemapOutput{*output}, progress)
+
+	stored, err := service.findById(context.Background(), edited.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, stored.Temperature, "edited records are left alone")
+	assert.Nil(t, stored.Pressure)
+
+	stored, err = service.findById(context.Background(), single.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Pressure)
+	require.NotNil(t, stored.FeelsLike)
+	assert.Equal(t, 1009, *stored.Pressure)
+	assert.Equal(t, 9.5, *stored.FeelsLike)
+	assert.Equal(t, "Londres", stored.CityName, "fields not read from the payload are kept")
+	assert.True(t, fetchedAt.Equal(stored.FetchedAt))
+
+	stored, err = service.findById(context.Background(), consensus.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Pressure)
+	assert.Equal(t, 1011, *stored.Pressure)
+	require.Len(t, stored.Readings, 2)
+	for _, reading := range stored.Readings {
+		if reading.Provider == "OpenMeteo" {
+			assert.Equal(t, 70, reading.Humidity)
+		}
+	}
+}
+
+func TestService_weatherById_Units(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	w := models.Weather{CityName: "Phoenix", Country: "US", Temperature: 40, WindSpeed: 2, FetchedAt: time.Now()}
+	require.NoError(t, db.Create(&w).Error)
+
+	result, err := service.weatherById(context.Background(), w.ID, Presentation{Units: UnitsImperial})
+
+	require.NoError(t, err)
+	assert.Equal(t, 104.0, result.Temperature)
+	assert.Equal(t, 4.47, result.WindSpeed)
+	assert.Equal(t, unitsOf(UnitsImperial), result.Units)
+
+	latest, err := service.latestByCityName(context.Background(), "Phoenix", LatestInput{Presentation: Presentation{Units: UnitsStandard}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 313.15, latest.Temperature)
+	assert.Equal(t, "K", latest.Units.Temperature)
+
+	list, err := service.paginatedList(context.Background(), ListInput{Page: 1, PageSize: 10})
+
+	require.NoError(t, err)
+	require.Len(t, list.Weathers, 1)
+	assert.Equal(t, 40.0, list.Weathers[0].Temperature)
+	assert.Equal(t, UnitsMetric, list.Units.System)
+
+	stored, err := service.findById(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, stored.Temperature, "weather is stored in metric units")
+}
+
+func TestService_weatherById_Language(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewService(db)
+
+	code := 800
+	w := models.Weather{
+		CityName:      "Paris",
+		Country:       "FR",
+		Description:   "clear sky",
+		ConditionCode: &code,
+		Provider:      "OpenWeather",
+		FetchedAt:     time.Now(),
+	}
+	require.NoError(t, db.Create(&w).Error)
+
+	result, err := service.weatherById(context.Background(), w.ID, Presentation{Language: "fr"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "ciel dégagé", result.Description)
+
+	description := "sunny"
+	_, err = service.update(context.Background(), w.ID, UpdateInput{Description: &description})
+	require.NoError(t, err)
+
+	result, err = service.weatherById(context.Background(), w.ID, Presentation{Language: "fr"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "sunny", result.Description, "an edited description is not translated")
+	assert.Nil(t, result.ConditionCode)
+}
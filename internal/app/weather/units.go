@@ -0,0 +1,146 @@
+package weather
+
+import (
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"math"
+)
+
+// Weather is stored in the metric unit system, not in SI: temperatures in Celsius rather than Kelvin and speeds in
+// meters per second, as every provider answers. Read endpoints convert it to the unit system asked for, standard
+// being the SI one.
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+	// UnitsStandard has temperatures in Kelvin
+	UnitsStandard = "standard"
+)
+
+// unitSystems lists the supported unit systems with their labels
+var unitSystems = map[string]Units{
+	UnitsMetric:   {System: UnitsMetric, Temperature: "°C", WindSpeed: "m/s"},
+	UnitsImperial: {System: UnitsImperial, Temperature: "°F", WindSpeed: "mph"},
+	UnitsStandard: {System: UnitsStandard, Temperature: "K", WindSpeed: "m/s"},
+}
+
+const metersPerSecondToMph = 3600 / 1609.344
+
+// Units labels the units of the converted fields of a response.
+type Units struct {
+	System      string `json:"system"`
+	Temperature string `json:"temperature"`
+	WindSpeed   string `json:"wind_speed"`
+}
+
+// unitsOf returns the labels of system, metric when it is empty.
+func unitsOf(system string) Units {
+	if units, ok := unitSystems[system]; ok {
+		return units
+	}
+
+	return unitSystems[UnitsMetric]
+}
+
+// convertWeather returns w with its temperatures and wind speeds, and those of its readings, in system.
+func convertWeather(w models.Weather, system string) models.Weather {
+	system = unitsOf(system).System
+	if system == UnitsMetric {
+		return w
+	}
+
+	w.Temperature = convertTemperature(w.Temperature, system)
+	w.FeelsLike = convertOptional(w.FeelsLike, system, convertTemperature)
+	w.TempMin = convertOptional(w.TempMin, system, convertTemperature)
+	w.TempMax = convertOptional(w.TempMax, system, convertTemperature)
+	w.WindSpeed = convertWindSpeed(w.WindSpeed, system)
+	w.WindGust = convertOptional(w.WindGust, system, convertWindSpeed)
+
+	if w.Readings != nil {
+		// the readings array is shared with w, which may be cached
+		readings := make([]models.WeatherReading, len(w.Readings))
+		for i, reading := range w.Readings {
+			reading.Temperature = convertTemperature(reading.Temperature, system)
+			reading.WindSpeed = convertWindSpeed(reading.WindSpeed, system)
+			readings[i] = reading
+		}
+
+		w.Readings = readings
+	}
+
+	return w
+}
+
+// convertForecast returns f with the temperatures and wind speeds of its entries in system.
+func convertForecast(f models.Forecast, system string) models.Forecast {
+	system = unitsOf(system).System
+	if system == UnitsMetric {
+		return f
+	}
+
+	// the entries array is shared with the stored forecast
+	entries := make([]models.ForecastEntry, len(f.Entries))
+	for i, entry := range f.Entries {
+		entry.Temperature = convertTemperature(entry.Temperature, system)
+		entry.WindSpeed = convertWindSpeed(entry.WindSpeed, system)
+		entries[i] = entry
+	}
+
+	f.Entries = entries
+
+	return f
+}
+
+// convertTemperature converts celsius to system, rounded to hundredths.
+func convertTemperature(celsius float64, system string) float64 {
+	switch system {
+	case UnitsImperial:
+		return round(celsius*9/5 + 32)
+	case UnitsStandard:
+		return round(celsius + 273.15)
+	default:
+		return celsius
+	}
+}
+
+// convertWindSpeed converts speed, in meters per second, to system, rounded to hundredths.
+func convertWindSpeed(speed float64, system string) float64 {
+	if system == UnitsImperial {
+		return round(speed * metersPerSecondToMph)
+	}
+
+	return speed
+}
+
+// temperatureToMetric converts value, a temperature in system, to celsius.
+func temperatureToMetric(value float64, system string) float64 {
+	switch system {
+	case UnitsImperial:
+		return (value - 32) * 5 / 9
+	case UnitsStandard:
+		return value - 273.15
+	default:
+		return value
+	}
+}
+
+// windSpeedToMetric converts speed, in system, to meters per second.
+func windSpeedToMetric(speed float64, system string) float64 {
+	if system == UnitsImperial {
+		return speed / metersPerSecondToMph
+	}
+
+	return speed
+}
+
+func convertOptional(value *float64, system string, convert func(float64, string) float64) *float64 {
+	if value == nil {
+		return nil
+	}
+
+	converted := convert(*value, system)
+
+	return &converted
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,114 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertWeather(t *testing.T) {
+	feelsLike, gust := 18.5, 10.0
+	w := models.Weather{
+		Temperature: 20,
+		FeelsLike:   &feelsLike,
+		WindSpeed:   5,
+		WindGust:    &gust,
+		Humidity:    60,
+		Readings:    []models.WeatherReading{{Provider: "A", Temperature: -40, WindSpeed: 1}},
+	}
+
+	tests := []struct {
+		name                string
+		system              string
+		expectedTemperature float64
+		expectedFeelsLike   float64
+		expectedWindSpeed   float64
+		expectedWindGust    float64
+		expectedReading     models.WeatherReading
+	}{
+		{
+			name:                "metric is stored as is",
+			system:              UnitsMetric,
+			expectedTemperature: 20,
+			expectedFeelsLike:   18.5,
+			expectedWindSpeed:   5,
+			expectedWindGust:    10,
+			expectedReading:     models.WeatherReading{Provider: "A", Temperature: -40, WindSpeed: 1},
+		},
+		{
+			name:                "empty system is metric",
+			system:              "",
+			expectedTemperature: 20,
+			expectedFeelsLike:   18.5,
+			expectedWindSpeed:   5,
+			expectedWindGust:    10,
+			expectedReading:     models.WeatherReading{Provider: "A", Temperature: -40, WindSpeed: 1},
+		},
+		{
+			name:                "imperial",
+			system:              UnitsImperial,
+			expectedTemperature: 68,
+			expectedFeelsLike:   65.3,
+			expectedWindSpeed:   11.18,
+			expectedWindGust:    22.37,
+			expectedReading:     models.WeatherReading{Provider: "A", Temperature: -40, WindSpeed: 2.24},
+		},
+		{
+			name:                "standard",
+			system:              UnitsStandard,
+			expectedTemperature: 293.15,
+			expectedFeelsLike:   291.65,
+			expectedWindSpeed:   5,
+			expectedWindGust:    10,
+			expectedReading:     models.WeatherReading{Provider: "A", Temperature: 233.15, WindSpeed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convertWeather(w, tt.system)
+
+			assert.Equal(t, tt.expectedTemperature, result.Temperature)
+			assert.Equal(t, tt.expectedFeelsLike, *result.FeelsLike)
+			assert.Equal(t, tt.expectedWindSpeed, result.WindSpeed)
+			assert.Equal(t, tt.expectedWindGust, *result.WindGust)
+			assert.Equal(t, []models.WeatherReading{tt.expectedReading}, result.Readings)
+			assert.Equal(t, 60, result.Humidity)
+			assert.Nil(t, result.TempMin)
+		})
+	}
+
+	t.Run("source weather is left unchanged", func(t *testing.T) {
+		convertWeather(w, UnitsImperial)
+
+		assert.Equal(t, 20.0, w.Temperature)
+		assert.Equal(t, 18.5, *w.FeelsLike)
+		assert.Equal(t, -40.0, w.Readings[0].Temperature)
+	})
+}
+
+func TestConvertForecast(t *testing.T) {
+	f := models.Forecast{CityName: "London", Entries: []models.ForecastEntry{{Temperature: 20, WindSpeed: 5, Humidity: 60}}}
+
+	assert.Equal(t, f, convertForecast(f, UnitsMetric))
+
+	result := convertForecast(f, UnitsImperial)
+
+	assert.Equal(t, []models.ForecastEntry{{Temperature: 68, WindSpeed: 11.18, Humidity: 60}}, result.Entries)
+	assert.Equal(t, 20.0, f.Entries[0].Temperature, "source forecast is left unchanged")
+}
+
+func TestUnitsOf(t *testing.T) {
+	assert.Equal(t, Units{System: UnitsImperial, Temperature: "°F", WindSpeed: "mph"}, unitsOf(UnitsImperial))
+	assert.Equal(t, Units{System: UnitsMetric, Temperature: "°C", WindSpeed: "m/s"}, unitsOf(""))
+}
+
+func TestToMetric(t *testing.T) {
+	for _, system := range []string{UnitsMetric, UnitsImperial, UnitsStandard} {
+		t.Run(system, func(t *testing.T) {
+			assert.InDelta(t, 21.5, temperatureToMetric(convertTemperature(21.5, system), system), 0.01)
+			assert.InDelta(t, 7.25, windSpeedToMetric(convertWindSpeed(7.25, system), system), 0.01)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// AirQuality is the air pollution fetched for a city: the AQI index, from 1 (good) to 5 (very poor), and the
+// concentrations of the pollutants, in μg/m3, nil when the provider didn't report them.
+type AirQuality struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;column:id" json:"id"`
+	CityName   string     `gorm:"type:varchar(255);not null;column:city_name" json:"city_name"`
+	Country    string     `gorm:"type:varchar(255);not null;column:country" json:"country"`
+	Latitude   float64    `gorm:"not null;column:latitude" json:"latitude"`
+	Longitude  float64    `gorm:"not null;column:longitude" json:"longitude"`
+	AQI        int        `gorm:"not null;column:aqi" json:"aqi"`
+	CO         *float64   `gorm:"column:co" json:"co"`
+	NO         *float64   `gorm:"column:no" json:"no"`
+	NO2        *float64   `gorm:"column:no2" json:"no2"`
+	O3         *float64   `gorm:"column:o3" json:"o3"`
+	SO2        *float64   `gorm:"column:so2" json:"so2"`
+	PM25       *float64   `gorm:"column:pm2_5" json:"pm2_5"`
+	PM10       *float64   `gorm:"column:pm10" json:"pm10"`
+	NH3        *float64   `gorm:"column:nh3" json:"nh3"`
+	ObservedAt *time.Time `gorm:"column:observed_at" json:"observed_at"`
+	Provider   string     `gorm:"type:varchar(255);column:provider" json:"provider"`
+	// LocationID is nil on records fetched while no provider could geocode the city
+	LocationID *uuid.UUID `gorm:"type:uuid;column:location_id" json:"location_id"`
+	Location   *Location  `gorm:"foreignKey:LocationID;constraint:OnDelete:SET NULL" json:"location,omitempty"`
+	FetchedAt  time.Time  `gorm:"not null;column:fetched_at" json:"fetched_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (a *AirQuality) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = uuid.New()
+	return
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Alert is an official weather warning issued for a city between StartsAt and EndsAt. An alert fetched again
+// updates the stored one of the same city, provider, sender, event and start, and an alert no longer fetched
+// for its city, withdrawn by its sender, ends when it was found missing.
+type Alert struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;column:id" json:"id"`
+	CityName  string    `gorm:"type:varchar(255);not null;column:city_name;uniqueIndex:alerts_identity_index,priority:1" json:"city_name"`
+	Country   string    `gorm:"type:varchar(255);not null;column:country;uniqueIndex:alerts_identity_index,priority:2" json:"country"`
+	Latitude  float64   `gorm:"not null;column:latitude" json:"latitude"`
+	Longitude float64   `gorm:"not null;column:longitude" json:"longitude"`
+	Provider  string    `gorm:"type:varchar(255);not null;column:provider;uniqueIndex:alerts_identity_index,priority:3" json:"provider"`
+	// Sender is the agency that issued the alert
+	Sender string `gorm:"type:varchar(255);not null;column:sender;uniqueIndex:alerts_identity_index,priority:4" json:"sender"`
+	Event  string `gorm:"type:varchar(255);not null;column:event;uniqueIndex:alerts_identity_index,priority:5" json:"event"`
+	// Severity is one of schemata.Severities
+	Severity    string    `gorm:"type:varchar(16);not null;column:severity" json:"severity"`
+	Description string    `gorm:"type:text;column:description" json:"description"`
+	Tags        []string  `gorm:"type:text;serializer:json;column:tags" json:"tags"`
+	StartsAt    time.Time `gorm:"not null;column:starts_at;uniqueIndex:alerts_identity_index,priority:6" json:"starts_at"`
+	EndsAt      time.Time `gorm:"not null;column:ends_at" json:"ends_at"`
+	// LocationID is nil on alerts fetched while no provider could geocode the city
+	LocationID *uuid.UUID `gorm:"type:uuid;column:location_id" json:"location_id"`
+	Location   *Location  `gorm:"foreignKey:LocationID;constraint:OnDelete:SET NULL" json:"location,omitempty"`
+	FetchedAt  time.Time  `gorm:"not null;column:fetched_at" json:"fetched_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (a *Alert) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = uuid.New()
+	return
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Forecast is the forecast series fetched for a city, served until ExpiresAt.
+type Forecast struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primaryKey;column:id" json:"id"`
+	CityName  string          `gorm:"type:varchar(255);not null;column:city_name" json:"city_name"`
+	Country   string          `gorm:"type:varchar(255);not null;column:country" json:"country"`
+	Provider  string          `gorm:"type:varchar(255);column:provider" json:"provider"`
+	Entries   []ForecastEntry `gorm:"type:text;serializer:json;column:entries" json:"entries"`
+	FetchedAt time.Time       `gorm:"not null;column:fetched_at" json:"fetched_at"`
+	ExpiresAt time.Time       `gorm:"not null;column:expires_at" json:"expires_at"`
+	CreatedAt time.Time       `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"column:updated_at" json:"updated_at"`
+	// LocationID is nil on forecasts fetched while no provider could geocode the city
+	LocationID *uuid.UUID `gorm:"type:uuid;column:location_id" json:"location_id"`
+}
+
+// ForecastEntry is the forecast for the period starting at Time.
+type ForecastEntry struct {
+	Time        time.Time `json:"time"`
+	Temperature float64   `json:"temperature"`
+	Description string    `json:"description"`
+	Humidity    int       `json:"humidity"`
+	WindSpeed   float64   `json:"wind_speed"`
+	// ConditionCode is the provider's code of the condition Description describes, nil on entries stored
+	// before codes were kept
+	ConditionCode *int `json:"condition_code"`
+}
+
+func (f *Forecast) BeforeCreate(tx *gorm.DB) (err error) {
+	f.ID = uuid.New()
+	return
+}
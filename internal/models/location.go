@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Location is a place weather is fetched for, resolved once through a provider's geocoding API
+// so that records of the same place share an identity whatever name it was asked by.
+type Location struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;column:id" json:"id"`
+	Name        string    `gorm:"type:varchar(255);not null;column:name" json:"name"`
+	CountryCode string    `gorm:"type:varchar(8);not null;uniqueIndex:locations_coordinates_index;column:country_code" json:"country_code"`
+	State       string    `gorm:"type:varchar(255);column:state" json:"state"`
+	Latitude    float64   `gorm:"not null;uniqueIndex:locations_coordinates_index;column:latitude" json:"latitude"`
+	Longitude   float64   `gorm:"not null;uniqueIndex:locations_coordinates_index;column:longitude" json:"longitude"`
+	Timezone    string    `gorm:"type:varchar(64);column:timezone" json:"timezone"`
+	// Aliases are the other names, lowercased, the location was asked by
+	Aliases   []string  `gorm:"type:text;serializer:json;column:aliases" json:"aliases"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (l *Location) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.New()
+	return
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ProviderQuota counts the calls made to a weather provider with one API key on one UTC day.
+type ProviderQuota struct {
+	Provider  string    `gorm:"type:varchar(255);primaryKey;column:provider" json:"provider"`
+	KeyID     string    `gorm:"type:varchar(64);primaryKey;column:key_id" json:"key_id"`
+	Day       string    `gorm:"type:varchar(10);primaryKey;column:day" json:"day"`
+	Calls     int64     `gorm:"not null;default:0;column:calls" json:"calls"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (ProviderQuota) TableName() string {
+	return "provider_quotas"
+}
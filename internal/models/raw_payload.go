@@ -0,0 +1,52 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// RawPayload is a response body stored verbatim, as JSONB on Postgres and text elsewhere.
+type RawPayload json.RawMessage
+
+// GormDBDataType picks the column type of the dialect db runs on.
+func (RawPayload) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "JSONB"
+	}
+
+	return "TEXT"
+}
+
+func (p RawPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	return string(p), nil
+}
+
+func (p *RawPayload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append(RawPayload(nil), v...)
+	case string:
+		*p = RawPayload(v)
+	default:
+		return fmt.Errorf("cannot scan %T into RawPayload", value)
+	}
+
+	return nil
+}
+
+func (p RawPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+
+	return p, nil
+}
@@ -1,25 +1,77 @@
 package models
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"time"
 )
 
+// Weather is stored in metric units, not SI: temperatures in Celsius, speeds in meters per second, pressure in hPa,
+// distances in meters and precipitation in mm, whatever units it is read in.
 type Weather struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey;column:id" json:"id"`
 	CityName    string    `gorm:"type:varchar(255);not null;column:city_name" json:"city_name"`
 	Country     string    `gorm:"type:varchar(255);not null;column:country" json:"country"`
 	Temperature float64   `gorm:"not null;column:temperature" json:"temperature"`
 	Description string    `gorm:"type:varchar(255);column:description" json:"description"`
-	Humidity    int       `gorm:"not null;column:humidity" json:"humidity"`
-	WindSpeed   float64   `gorm:"not null;column:wind_speed" json:"wind_speed"`
-	FetchedAt   time.Time `gorm:"not null;column:fetched_at" json:"fetched_at"`
-	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+	// ConditionCode is the provider's code of the condition described, nil on consensus records and once the
+	// description is edited. Condition and Intensity classify the weather whatever the provider, as
+	// schemata.Condition and schemata.Intensity, empty when the provider's code is unknown
+	ConditionCode *int    `gorm:"column:condition_code" json:"condition_code"`
+	Condition     string  `gorm:"type:varchar(32);not null;default:'';column:condition" json:"condition"`
+	Intensity     string  `gorm:"type:varchar(32);not null;default:'';column:intensity" json:"intensity"`
+	Humidity      int     `gorm:"not null;column:humidity" json:"humidity"`
+	WindSpeed     float64 `gorm:"not null;column:wind_speed" json:"wind_speed"`
+	// the observation fields below are nil when the provider didn't report them
+	Pressure   *int       `gorm:"column:pressure" json:"pressure"`
+	WindDeg    *int       `gorm:"column:wind_deg" json:"wind_deg"`
+	WindGust   *float64   `gorm:"column:wind_gust" json:"wind_gust"`
+	FeelsLike  *float64   `gorm:"column:feels_like" json:"feels_like"`
+	TempMin    *float64   `gorm:"column:temp_min" json:"temp_min"`
+	TempMax    *float64   `gorm:"column:temp_max" json:"temp_max"`
+	Visibility *int       `gorm:"column:visibility" json:"visibility"`
+	Clouds     *int       `gorm:"column:clouds" json:"clouds"`
+	Rain1h     *float64   `gorm:"column:rain_1h" json:"rain_1h"`
+	Rain3h     *float64   `gorm:"column:rain_3h" json:"rain_3h"`
+	Snow1h     *float64   `gorm:"column:snow_1h" json:"snow_1h"`
+	Snow3h     *float64   `gorm:"column:snow_3h" json:"snow_3h"`
+	Sunrise    *time.Time `gorm:"column:sunrise" json:"sunrise"`
+	Sunset     *time.Time `gorm:"column:sunset" json:"sunset"`
+	ObservedAt *time.Time `gorm:"uniqueIndex:weathers_backfilled_index,priority:2,where:backfilled;column:observed_at" json:"observed_at"`
+	Latitude   *float64   `gorm:"column:latitude" json:"latitude"`
+	Longitude  *float64   `gorm:"column:longitude" json:"longitude"`
+	Provider   string     `gorm:"type:varchar(255);column:provider" json:"provider"`
+	// LocationID is nil on records stored before locations existed or fetched by coordinates only
+	LocationID *uuid.UUID `gorm:"type:uuid;uniqueIndex:weathers_backfilled_index,priority:1;column:location_id" json:"location_id"`
+	Location   *Location  `gorm:"foreignKey:LocationID;constraint:OnDelete:SET NULL" json:"location,omitempty"`
+	FetchedAt  time.Time  `gorm:"not null;column:fetched_at" json:"fetched_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	// EditedAt is when the record was last edited through PUT /weather/{id}, nil when it never was. Edited
+	// records aren't remapped from their raw payload, which would undo the edits
+	EditedAt *time.Time `gorm:"column:edited_at" json:"edited_at"`
+
+	// Disagreements and Readings are only set on records fetched in consensus mode
+	Disagreements []string         `gorm:"type:text;serializer:json;column:disagreements" json:"disagreements,omitempty"`
+	Readings      []WeatherReading `gorm:"foreignKey:WeatherID;constraint:OnDelete:CASCADE" json:"readings,omitempty"`
+
+	// Raw is the provider response body the record was mapped from, served by GET /weather/{id}/raw rather than
+	// with the record. It is nil on records stored before it was kept and on backfilled ones, and holds a
+	// ConsensusPayload per reading on consensus records
+	Raw RawPayload `gorm:"column:raw" json:"-"`
+
+	// Backfilled is set on the past observations stored by Backfill, stored once per location and observation time
+	Backfilled bool `gorm:"not null;default:false;column:backfilled" json:"-"`
 }
 
 func (w *Weather) BeforeCreate(tx *gorm.DB) (err error) {
 	w.ID = uuid.New()
 	return
 }
+
+// ConsensusPayload is the raw response of one of the providers a consensus Weather was merged from.
+type ConsensusPayload struct {
+	Provider string          `json:"provider"`
+	Payload  json.RawMessage `json:"payload"`
+}
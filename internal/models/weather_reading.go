@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// WeatherReading is a single provider's observation a consensus Weather was computed from.
+type WeatherReading struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;column:id" json:"id"`
+	WeatherID   uuid.UUID `gorm:"type:uuid;not null;column:weather_id" json:"weather_id"`
+	Provider    string    `gorm:"type:varchar(255);not null;column:provider" json:"provider"`
+	Temperature float64   `gorm:"not null;column:temperature" json:"temperature"`
+	Description string    `gorm:"type:varchar(255);column:description" json:"description"`
+	// ConditionCode is the provider's code of the condition described
+	ConditionCode *int      `gorm:"column:condition_code" json:"condition_code"`
+	Humidity      int       `gorm:"not null;column:humidity" json:"humidity"`
+	WindSpeed     float64   `gorm:"not null;column:wind_speed" json:"wind_speed"`
+	CreatedAt     time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (r *WeatherReading) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	return
+}
@@ -2,9 +2,14 @@ package http
 
 import (
 	"errors"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/weather"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_meteo"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_weather"
 	"gorm.io/gorm"
+	"math"
 	"net/http"
+	"time"
 )
 
 func MapErrorToHttpStatusCode(err error) int {
@@ -13,11 +18,39 @@ func MapErrorToHttpStatusCode(err error) int {
 	}
 
 	switch {
-	case errors.Is(err, open_weather.NotFoundErr), errors.Is(err, gorm.ErrRecordNotFound):
+	case errors.Is(err, weather_api.NotFoundErr), errors.Is(err, gorm.ErrRecordNotFound):
 		return http.StatusNotFound
-	case errors.Is(err, open_weather.UnhandledError):
+	case errors.Is(err, open_weather.UnhandledError), errors.Is(err, open_meteo.UnhandledError),
+		errors.Is(err, open_weather.RateLimitedErr), errors.Is(err, open_meteo.RateLimitedErr),
+		errors.Is(err, weather_api.ProvidersExhaustedErr), errors.Is(err, weather_api.CircuitOpenErr),
+		errors.Is(err, weather_api.RateLimitExceededErr), errors.Is(err, weather_api.QuotaExceededErr):
 		return http.StatusServiceUnavailable
+	case errors.Is(err, weather_api.CapabilityNotSupportedErr):
+		return http.StatusNotImplemented
+	case errors.Is(err, weather.InvalidCursorErr):
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
 }
+
+// RetryAfter returns how long the client should wait before retrying a request that
+// failed with err, when err carries that information.
+func RetryAfter(err error) (time.Duration, bool) {
+	var delay time.Duration
+
+	var circuitOpenErr *weather_api.CircuitOpenError
+	var limitErr *weather_api.LimitError
+
+	switch {
+	case errors.As(err, &circuitOpenErr):
+		delay = circuitOpenErr.RetryAfter
+	case errors.As(err, &limitErr):
+		delay = limitErr.RetryAfter
+	default:
+		return 0, false
+	}
+
+	// Retry-After has a resolution of seconds, so round up and never advertise 0
+	return time.Duration(math.Max(1, math.Ceil(delay.Seconds()))) * time.Second, true
+}
@@ -2,10 +2,16 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/repositories/weather"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_meteo"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_weather"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
@@ -26,11 +32,56 @@ func TestMapErrorToHttpStatusCode(t *testing.T) {
 			err:            gorm.ErrRecordNotFound,
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:           "should return 400 for weather.InvalidCursorErr",
+			err:            weather.InvalidCursorErr,
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "should return 503 for open_weather.UnhandledError",
 			err:            open_weather.UnhandledError,
 			expectedStatus: http.StatusServiceUnavailable,
 		},
+		{
+			name:           "should return 404 for open_meteo.NotFoundErr",
+			err:            open_meteo.NotFoundErr,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "should return 404 for the not found error of any provider",
+			err:            fmt.Errorf("geocoding: %w", schemata.NotFoundErr),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "should return 503 for open_meteo.UnhandledError",
+			err:            open_meteo.UnhandledError,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "should return 503 for open_weather.RateLimitedErr",
+			err:            open_weather.RateLimitedErr,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "should return 503 for weather_api.ProvidersExhaustedErr",
+			err:            weather_api.ProvidersExhaustedErr,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "should return 503 for weather_api.CircuitOpenError",
+			err:            &weather_api.CircuitOpenError{Provider: "OpenWeather", RetryAfter: time.Second},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "should return 503 for weather_api.LimitError",
+			err:            &weather_api.LimitError{Err: weather_api.RateLimitExceededErr},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "should return 501 for weather_api.CapabilityNotSupportedErr",
+			err:            weather_api.CapabilityNotSupportedErr,
+			expectedStatus: http.StatusNotImplemented,
+		},
 		{
 			name:           "should return 0 for nil error",
 			err:            nil,
@@ -131,3 +182,50 @@ func TestMapErrorToHttpStatusCode_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expectedDelay time.Duration
+		expectedOk    bool
+	}{
+		{
+			name:          "circuit open error",
+			err:           &weather_api.CircuitOpenError{RetryAfter: 1500 * time.Millisecond},
+			expectedDelay: 2 * time.Second,
+			expectedOk:    true,
+		},
+		{
+			name:          "circuit open error joined with other failures",
+			err:           errors.Join(weather_api.ProvidersExhaustedErr, &weather_api.CircuitOpenError{RetryAfter: 10 * time.Second}),
+			expectedDelay: 10 * time.Second,
+			expectedOk:    true,
+		},
+		{
+			name:          "never advertises zero",
+			err:           &weather_api.CircuitOpenError{},
+			expectedDelay: time.Second,
+			expectedOk:    true,
+		},
+		{
+			name:          "limit error",
+			err:           &weather_api.LimitError{Err: weather_api.QuotaExceededErr, RetryAfter: time.Hour},
+			expectedDelay: time.Hour,
+			expectedOk:    true,
+		},
+		{
+			name:       "other error",
+			err:        open_weather.UnhandledError,
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := RetryAfter(tt.err)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedDelay, delay)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package like
+
+import "strings"
+
+// escaper escapes the wildcards of LIKE patterns written with ESCAPE '\'.
+var escaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Escape returns s matching itself literally in LIKE patterns written with ESCAPE '\'.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,34 @@
+package air_quality
+
+import (
+	"context"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return Repository{
+		db: db,
+	}
+}
+
+func (r Repository) Create(ctx context.Context, a *models.AirQuality) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+func (r Repository) LatestByCityName(ctx context.Context, cityName string) (a *models.AirQuality, err error) {
+	err = r.db.WithContext(ctx).Where("LOWER(city_name) = LOWER(?)", cityName).Order("created_at DESC").First(&a).Error
+
+	return a, err
+}
+
+func (r Repository) LatestByLocationID(ctx context.Context, locationID uuid.UUID) (a *models.AirQuality, err error) {
+	err = r.db.WithContext(ctx).Preload("Location").Where("location_id = ?", locationID).Order("created_at DESC").First(&a).Error
+
+	return a, err
+}
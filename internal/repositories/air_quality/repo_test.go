@@ -0,0 +1,92 @@
+package air_quality
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Location{}, &models.AirQuality{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRepository_Create(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	pm25 := 12.5
+	a := &models.AirQuality{
+		CityName:  "London",
+		Country:   "GB",
+		Latitude:  51.5074,
+		Longitude: -0.1278,
+		AQI:       2,
+		PM25:      &pm25,
+		Provider:  "OpenWeather",
+		FetchedAt: time.Now(),
+	}
+
+	err := repo.Create(context.Background(), a)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, a.ID)
+
+	var stored models.AirQuality
+	require.NoError(t, db.First(&stored, "id = ?", a.ID).Error)
+	assert.Equal(t, 2, stored.AQI)
+	require.NotNil(t, stored.PM25)
+	assert.Equal(t, 12.5, *stored.PM25)
+	assert.Nil(t, stored.CO)
+}
+
+func TestRepository_Latest(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	london := models.Location{Name: "London", CountryCode: "GB"}
+	require.NoError(t, db.Create(&london).Error)
+
+	now := time.Now()
+	airQualities := []models.AirQuality{
+		{CityName: "London", Country: "GB", Provider: "Old", LocationID: &london.ID, CreatedAt: now.Add(-time.Hour)},
+		{CityName: "London", Country: "GB", Provider: "New", LocationID: &london.ID, CreatedAt: now},
+		{CityName: "Paris", Country: "FR", Provider: "Paris", CreatedAt: now},
+	}
+	for i := range airQualities {
+		require.NoError(t, db.Create(&airQualities[i]).Error)
+	}
+
+	t.Run("by city name", func(t *testing.T) {
+		result, err := repo.LatestByCityName(ctx, "LONDON")
+		require.NoError(t, err)
+		assert.Equal(t, "New", result.Provider)
+
+		_, err = repo.LatestByCityName(ctx, "Berlin")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("by location", func(t *testing.T) {
+		result, err := repo.LatestByLocationID(ctx, london.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "New", result.Provider)
+		require.NotNil(t, result.Location)
+		assert.Equal(t, "London", result.Location.Name)
+
+		_, err = repo.LatestByLocationID(ctx, uuid.New())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
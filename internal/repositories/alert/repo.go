@@ -0,0 +1,102 @@
+package alert
+
+import (
+	"context"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return Repository{
+		db: db,
+	}
+}
+
+// Store creates a, or updates the stored alert of the same city, provider, sender, event and start with it,
+// setting a's ID and CreatedAt to the stored one's.
+func (r Repository) Store(ctx context.Context, a *models.Alert) error {
+	err := r.db.WithContext(ctx).Omit("Location").
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "city_name"}, {Name: "country"}, {Name: "provider"}, {Name: "sender"}, {Name: "event"}, {Name: "starts_at"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"latitude", "longitude", "severity", "description", "tags", "ends_at", "location_id", "fetched_at", "updated_at",
+			}),
+		}).
+		Create(a).Error
+	if err != nil {
+		return err
+	}
+
+	var stored models.Alert
+	err = r.db.WithContext(ctx).Select("id", "created_at").
+		Where("city_name = ? AND country = ? AND provider = ?", a.CityName, a.Country, a.Provider).
+		Where("sender = ? AND event = ? AND starts_at = ?", a.Sender, a.Event, a.StartsAt).
+		First(&stored).Error
+	if err != nil {
+		return err
+	}
+
+	a.ID, a.CreatedAt = stored.ID, stored.CreatedAt
+
+	return nil
+}
+
+// Expire ends at at the alerts ending after at of the location with locationID, or of cityName in country
+// when locationID is nil, but the ones with keptIDs: the alerts withdrawn since they were stored.
+func (r Repository) Expire(ctx context.Context, locationID *uuid.UUID, cityName, country string, keptIDs []uuid.UUID, at time.Time) error {
+	query := r.db.WithContext(ctx).Model(&models.Alert{}).Where("ends_at > ?", at)
+	if locationID != nil {
+		query = query.Where("location_id = ?", *locationID)
+	} else {
+		query = query.Where("LOWER(city_name) = LOWER(?)", cityName)
+		if country != "" {
+			query = query.Where("LOWER(country) = LOWER(?)", country)
+		}
+	}
+	if len(keptIDs) > 0 {
+		query = query.Where("id NOT IN ?", keptIDs)
+	}
+
+	return query.Updates(map[string]interface{}{"ends_at": at, "updated_at": time.Now()}).Error
+}
+
+// ListByCityName returns the alerts stored for cityName, in country when it isn't empty, ending after at,
+// by start.
+func (r Repository) ListByCityName(ctx context.Context, cityName, country string, at time.Time) (alerts []models.Alert, err error) {
+	query := r.db.WithContext(ctx).Where("LOWER(city_name) = LOWER(?)", cityName).Where("ends_at > ?", at)
+	if country != "" {
+		query = query.Where("LOWER(country) = LOWER(?)", country)
+	}
+
+	err = query.Order("starts_at").Find(&alerts).Error
+
+	return alerts, err
+}
+
+// ListByLocationID returns the alerts stored for the location with locationID ending after at, by start.
+func (r Repository) ListByLocationID(ctx context.Context, locationID uuid.UUID, at time.Time) (alerts []models.Alert, err error) {
+	err = r.db.WithContext(ctx).Preload("Location").
+		Where("location_id = ?", locationID).
+		Where("ends_at > ?", at).
+		Order("starts_at").
+		Find(&alerts).Error
+
+	return alerts, err
+}
+
+// ListActive returns the alerts of every city in effect at at, by start.
+func (r Repository) ListActive(ctx context.Context, at time.Time) (alerts []models.Alert, err error) {
+	err = r.db.WithContext(ctx).Preload("Location").
+		Where("starts_at <= ? AND ends_at > ?", at, at).
+		Order("starts_at").
+		Find(&alerts).Error
+
+	return alerts, err
+}
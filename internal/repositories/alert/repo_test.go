@@ -0,0 +1,172 @@
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Location{}, &models.Alert{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRepository_Store(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	start := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	first := &models.Alert{
+		CityName:    "London",
+		Country:     "GB",
+		Provider:    "OpenWeather",
+		Sender:      "Met Office",
+		Event:       "Yellow wind warning",
+		Severity:    "moderate",
+		Description: "Strong winds.",
+		Tags:        []string{"Wind"},
+		StartsAt:    start,
+		EndsAt:      start.Add(6 * time.Hour),
+		FetchedAt:   time.Now(),
+	}
+	require.NoError(t, repo.Store(ctx, first))
+	assert.NotEqual(t, uuid.Nil, first.ID)
+
+	t.Run("the same alert fetched again updates the stored one", func(t *testing.T) {
+		again := &models.Alert{
+			CityName:    "London",
+			Country:     "GB",
+			Provider:    "OpenWeather",
+			Sender:      "Met Office",
+			Event:       "Yellow wind warning",
+			Severity:    "moderate",
+			Description: "Strong winds, extended.",
+			Tags:        []string{"Wind", "Coastal event"},
+			StartsAt:    start,
+			EndsAt:      start.Add(12 * time.Hour),
+			FetchedAt:   time.Now(),
+		}
+		require.NoError(t, repo.Store(ctx, again))
+		assert.Equal(t, first.ID, again.ID)
+
+		var stored []models.Alert
+		require.NoError(t, db.Find(&stored).Error)
+		require.Len(t, stored, 1)
+		assert.Equal(t, "Strong winds, extended.", stored[0].Description)
+		assert.Equal(t, []string{"Wind", "Coastal event"}, stored[0].Tags)
+		assert.True(t, start.Add(12*time.Hour).Equal(stored[0].EndsAt))
+	})
+
+	t.Run("another event is another alert", func(t *testing.T) {
+		other := &models.Alert{CityName: "London", Country: "GB", Provider: "OpenWeather", Sender: "Met Office", Event: "Yellow rain warning", StartsAt: start, EndsAt: start.Add(time.Hour)}
+		require.NoError(t, repo.Store(ctx, other))
+		assert.NotEqual(t, first.ID, other.ID)
+	})
+}
+
+func TestRepository_Expire(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	london := models.Location{Name: "London", CountryCode: "GB"}
+	require.NoError(t, db.Create(&london).Error)
+
+	now := time.Now()
+	alerts := []models.Alert{
+		{CityName: "London", Country: "GB", Event: "Kept", LocationID: &london.ID, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{CityName: "London", Country: "GB", Event: "Withdrawn", LocationID: &london.ID, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+		{CityName: "London", Country: "CA", Event: "Canada", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+	}
+	for i := range alerts {
+		require.NoError(t, db.Create(&alerts[i]).Error)
+	}
+
+	t.Run("by location", func(t *testing.T) {
+		require.NoError(t, repo.Expire(ctx, &london.ID, "London", "GB", []uuid.UUID{alerts[0].ID}, now))
+
+		result, err := repo.ListByLocationID(ctx, london.ID, now)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Kept", result[0].Event)
+
+		var withdrawn models.Alert
+		require.NoError(t, db.First(&withdrawn, "id = ?", alerts[1].ID).Error)
+		assert.True(t, now.Equal(withdrawn.EndsAt))
+	})
+
+	t.Run("by city name with nothing kept", func(t *testing.T) {
+		require.NoError(t, repo.Expire(ctx, nil, "london", "ca", nil, now))
+
+		result, err := repo.ListByCityName(ctx, "London", "", now)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Kept", result[0].Event)
+	})
+}
+
+func TestRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	london := models.Location{Name: "London", CountryCode: "GB"}
+	require.NoError(t, db.Create(&london).Error)
+
+	now := time.Now()
+	alerts := []models.Alert{
+		{CityName: "London", Country: "GB", Event: "Active", LocationID: &london.ID, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{CityName: "London", Country: "GB", Event: "Upcoming", LocationID: &london.ID, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+		{CityName: "London", Country: "GB", Event: "Ended", LocationID: &london.ID, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+		{CityName: "London", Country: "CA", Event: "Canada", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+	}
+	for i := range alerts {
+		require.NoError(t, db.Create(&alerts[i]).Error)
+	}
+
+	events := func(alerts []models.Alert) []string {
+		events := make([]string, 0, len(alerts))
+		for _, a := range alerts {
+			events = append(events, a.Event)
+		}
+
+		return events
+	}
+
+	t.Run("by city name", func(t *testing.T) {
+		result, err := repo.ListByCityName(ctx, "LONDON", "gb", now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Active", "Upcoming"}, events(result))
+
+		result, err = repo.ListByCityName(ctx, "london", "", now)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Active", "Upcoming", "Canada"}, events(result))
+	})
+
+	t.Run("by location", func(t *testing.T) {
+		result, err := repo.ListByLocationID(ctx, london.ID, now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Active", "Upcoming"}, events(result))
+		require.NotNil(t, result[0].Location)
+		assert.Equal(t, "London", result[0].Location.Name)
+	})
+
+	t.Run("active", func(t *testing.T) {
+		result, err := repo.ListActive(ctx, now)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Active", "Canada"}, events(result))
+	})
+}
@@ -0,0 +1,41 @@
+package forecast
+
+import (
+	"context"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return Repository{
+		db: db,
+	}
+}
+
+func (r Repository) Create(ctx context.Context, f *models.Forecast) error {
+	return r.db.WithContext(ctx).Create(f).Error
+}
+
+// LatestByCityName returns the latest forecast stored for cityName, in country when it isn't empty.
+func (r Repository) LatestByCityName(ctx context.Context, cityName, country string) (f *models.Forecast, err error) {
+	query := r.db.WithContext(ctx).Where("LOWER(city_name) = LOWER(?)", cityName)
+	if country != "" {
+		query = query.Where("LOWER(country) = LOWER(?)", country)
+	}
+
+	err = query.Order("created_at DESC").First(&f).Error
+
+	return f, err
+}
+
+// LatestByLocationID returns the latest forecast stored for the location with locationID, whatever name it was asked by.
+func (r Repository) LatestByLocationID(ctx context.Context, locationID uuid.UUID) (f *models.Forecast, err error) {
+	err = r.db.WithContext(ctx).Where("location_id = ?", locationID).Order("created_at DESC").First(&f).Error
+
+	return f, err
+}
@@ -0,0 +1,115 @@
+package forecast
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Forecast{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRepository_Create(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	entryTime := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	f := &models.Forecast{
+		CityName:  "London",
+		Country:   "GB",
+		Provider:  "OpenWeather",
+		Entries:   []models.ForecastEntry{{Time: entryTime, Temperature: 18.5, Description: "light rain", Humidity: 80, WindSpeed: 4}},
+		FetchedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	err := repo.Create(context.Background(), f)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "", f.ID.String())
+
+	var stored models.Forecast
+	require.NoError(t, db.First(&stored, "id = ?", f.ID).Error)
+	require.Len(t, stored.Entries, 1)
+	assert.True(t, entryTime.Equal(stored.Entries[0].Time))
+	assert.Equal(t, "light rain", stored.Entries[0].Description)
+}
+
+func TestRepository_LatestByCityName(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	now := time.Now()
+	forecasts := []models.Forecast{
+		{CityName: "London", Country: "GB", Provider: "Old", CreatedAt: now.Add(-time.Hour)},
+		{CityName: "London", Country: "GB", Provider: "New", CreatedAt: now},
+		{CityName: "London", Country: "CA", Provider: "Canada", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	for i := range forecasts {
+		require.NoError(t, db.Create(&forecasts[i]).Error)
+	}
+
+	tests := []struct {
+		name             string
+		cityName         string
+		country          string
+		expectedProvider string
+		expectedError    error
+	}{
+		{name: "latest of any country", cityName: "london", expectedProvider: "New"},
+		{name: "latest of a country", cityName: "London", country: "ca", expectedProvider: "Canada"},
+		{name: "not found", cityName: "Paris", expectedError: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.LatestByCityName(context.Background(), tt.cityName, tt.country)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedProvider, result.Provider)
+		})
+	}
+}
+
+func TestRepository_LatestByLocationID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	locationID, otherID := uuid.New(), uuid.New()
+	now := time.Now()
+	forecasts := []models.Forecast{
+		{CityName: "Lisbon", Country: "PT", Provider: "Old", LocationID: &locationID, CreatedAt: now.Add(-time.Hour)},
+		{CityName: "Lisbon", Country: "PT", Provider: "New", LocationID: &locationID, CreatedAt: now},
+		{CityName: "Porto", Country: "PT", Provider: "Other", LocationID: &otherID, CreatedAt: now.Add(time.Hour)},
+	}
+	for i := range forecasts {
+		require.NoError(t, db.Create(&forecasts[i]).Error)
+	}
+
+	result, err := repo.LatestByLocationID(context.Background(), locationID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "New", result.Provider)
+
+	_, err = repo.LatestByLocationID(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,127 @@
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/pkg/like"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return Repository{
+		db: db,
+	}
+}
+
+func (r Repository) Create(ctx context.Context, l *models.Location) error {
+	return r.db.WithContext(ctx).Create(l).Error
+}
+
+// CreateOrFind stores l unless a location of the same country and coordinates is already stored, which
+// is then loaded into l. The unique index on them decides, so that concurrent requests for a new place
+// don't store it twice.
+func (r Repository) CreateOrFind(ctx context.Context, l *models.Location) error {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "country_code"}, {Name: "latitude"}, {Name: "longitude"}}, DoNothing: true}).
+		Create(l)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	var stored models.Location
+	err := r.db.WithContext(ctx).
+		Where("country_code = ? AND latitude = ? AND longitude = ?", l.CountryCode, l.Latitude, l.Longitude).
+		First(&stored).Error
+	if err != nil {
+		return err
+	}
+
+	*l = stored
+
+	return nil
+}
+
+func (r Repository) FindById(ctx context.Context, id uuid.UUID) (l *models.Location, err error) {
+	err = r.db.WithContext(ctx).Where("id = ?", id).First(&l).Error
+
+	return l, err
+}
+
+// AddAlias appends alias to the aliases of the location id unless it already has it. The JSON encoded
+// column is appended to in a single statement, so that aliases added concurrently aren't lost.
+func (r Repository) AddAlias(ctx context.Context, id uuid.UUID, alias string) error {
+	encoded, err := json.Marshal(alias)
+	if err != nil {
+		return err
+	}
+
+	appended := gorm.Expr(
+		"CASE WHEN aliases IS NULL OR aliases IN ('', 'null', '[]') THEN '[' || ? || ']' ELSE SUBSTR(aliases, 1, LENGTH(aliases) - 1) || ',' || ? || ']' END",
+		string(encoded), string(encoded),
+	)
+
+	return r.db.WithContext(ctx).Model(&models.Location{}).
+		Where("id = ?", id).
+		Where("aliases IS NULL OR aliases NOT LIKE ? ESCAPE '\\'", aliasPattern(alias)).
+		Update("aliases", appended).Error
+}
+
+// FindByName returns the location called name, or known by the alias name, in country when it
+// isn't empty. name is expected lowercased, like aliases are stored.
+func (r Repository) FindByName(ctx context.Context, name, country string) (l *models.Location, err error) {
+	query := r.db.WithContext(ctx).Where("LOWER(name) = ? OR aliases LIKE ? ESCAPE '\\'", name, aliasPattern(name))
+	if country != "" {
+		query = query.Where("UPPER(country_code) = UPPER(?)", country)
+	}
+
+	err = query.Order("created_at").First(&l).Error
+
+	return l, err
+}
+
+// ListByPrefix returns up to limit locations of country, when it isn't empty, whose name or one of
+// whose aliases starts with prefix, the ones named prefix first, then by name. prefix is expected
+// lowercased, like aliases are stored.
+func (r Repository) ListByPrefix(ctx context.Context, prefix, country string, limit int) (locations []models.Location, err error) {
+	escaped := like.Escape(prefix)
+
+	query := r.db.WithContext(ctx).Where("LOWER(name) LIKE ? ESCAPE '\\' OR aliases LIKE ? ESCAPE '\\'", escaped+"%", `%"`+escaped+"%")
+	if country != "" {
+		query = query.Where("UPPER(country_code) = UPPER(?)", country)
+	}
+
+	order := clause.Expr{
+		SQL:  "CASE WHEN LOWER(name) = ? OR aliases LIKE ? ESCAPE '\\' THEN 0 ELSE 1 END, name",
+		Vars: []any{prefix, aliasPattern(prefix)},
+	}
+
+	err = query.Order(clause.OrderBy{Expression: order}).Limit(limit).Find(&locations).Error
+
+	return locations, err
+}
+
+// FindNear returns a location of country within delta degrees of latitude and longitude.
+func (r Repository) FindNear(ctx context.Context, country string, latitude, longitude, delta float64) (l *models.Location, err error) {
+	err = r.db.WithContext(ctx).
+		Where("UPPER(country_code) = UPPER(?)", country).
+		Where("latitude BETWEEN ? AND ?", latitude-delta, latitude+delta).
+		Where("longitude BETWEEN ? AND ?", longitude-delta, longitude+delta).
+		Order("created_at").
+		First(&l).Error
+
+	return l, err
+}
+
+// aliasPattern matches the JSON encoded aliases column holding alias.
+func aliasPattern(alias string) string {
+	encoded, _ := json.Marshal(alias)
+
+	return "%" + like.Escape(string(encoded)) + "%"
+}
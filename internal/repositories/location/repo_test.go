@@ -0,0 +1,157 @@
+package location
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Location{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRepository_FindByName(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	london := &models.Location{Name: "London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278, Aliases: []string{"londres", "greater_london"}}
+	require.NoError(t, repo.Create(context.Background(), london))
+	require.NoError(t, repo.Create(context.Background(), &models.Location{Name: "London", CountryCode: "CA", Latitude: 42.9834, Longitude: -81.233}))
+
+	tests := []struct {
+		name     string
+		cityName string
+		country  string
+		found    bool
+	}{
+		{name: "by name", cityName: "london", country: "gb", found: true},
+		{name: "by alias", cityName: "londres", found: true},
+		{name: "alias in another country", cityName: "londres", country: "CA", found: false},
+		{name: "underscores are not wildcards", cityName: "greaterxlondon", found: false},
+		{name: "part of an alias", cityName: "londre", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := repo.FindByName(context.Background(), tt.cityName, tt.country)
+
+			if !tt.found {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, london.ID, l.ID)
+		})
+	}
+}
+
+func TestRepository_FindNear(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	london := &models.Location{Name: "London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278}
+	require.NoError(t, repo.Create(context.Background(), london))
+
+	l, err := repo.FindNear(context.Background(), "gb", 51.5085, -0.1257, 0.05)
+	require.NoError(t, err)
+	assert.Equal(t, london.ID, l.ID)
+
+	_, err = repo.FindNear(context.Background(), "GB", 51.75, -0.1278, 0.05)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = repo.FindNear(context.Background(), "CA", 51.5074, -0.1278, 0.05)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRepository_AddAlias(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	london := &models.Location{Name: "London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278}
+	require.NoError(t, repo.Create(context.Background(), london))
+
+	require.NoError(t, repo.AddAlias(context.Background(), london.ID, "londres"))
+	require.NoError(t, repo.AddAlias(context.Background(), london.ID, `"lon_don"`))
+	require.NoError(t, repo.AddAlias(context.Background(), london.ID, "londres"))
+
+	l, err := repo.FindByName(context.Background(), "londres", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"londres", `"lon_don"`}, l.Aliases, "an alias is added once, whatever its characters")
+}
+
+func TestRepository_CreateOrFind(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	london := &models.Location{Name: "London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278}
+	require.NoError(t, repo.CreateOrFind(context.Background(), london))
+
+	again := &models.Location{Name: "City of London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278}
+	require.NoError(t, repo.CreateOrFind(context.Background(), again))
+	assert.Equal(t, london.ID, again.ID)
+	assert.Equal(t, "London", again.Name, "the stored location is loaded")
+
+	var count int64
+	require.NoError(t, db.Model(&models.Location{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRepository_FindById(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	london := &models.Location{Name: "London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278}
+	require.NoError(t, repo.Create(context.Background(), london))
+
+	l, err := repo.FindById(context.Background(), london.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "London", l.Name)
+
+	_, err = repo.FindById(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRepository_ListByPrefix(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+
+	require.NoError(t, repo.Create(context.Background(), &models.Location{Name: "Lyon", CountryCode: "FR", Latitude: 45.764, Longitude: 4.8357}))
+	require.NoError(t, repo.Create(context.Background(), &models.Location{Name: "London", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278}))
+	require.NoError(t, repo.Create(context.Background(), &models.Location{Name: "Paris", CountryCode: "FR", Latitude: 48.8566, Longitude: 2.3522, Aliases: []string{"lutetia"}}))
+
+	locations, err := repo.ListByPrefix(context.Background(), "l", "", 10)
+	require.NoError(t, err)
+	require.Len(t, locations, 3)
+	assert.Equal(t, "London", locations[0].Name)
+	assert.Equal(t, "Lyon", locations[1].Name)
+	assert.Equal(t, "Paris", locations[2].Name, "matched by alias")
+
+	locations, err = repo.ListByPrefix(context.Background(), "l", "fr", 1)
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, "Lyon", locations[0].Name)
+
+	locations, err = repo.ListByPrefix(context.Background(), "%", "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, locations)
+
+	require.NoError(t, repo.Create(context.Background(), &models.Location{Name: "Lutetia Park", CountryCode: "US", Latitude: 40.7, Longitude: -74}))
+
+	locations, err = repo.ListByPrefix(context.Background(), "lutetia", "", 1)
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, "Paris", locations[0].Name, "named prefix first")
+}
@@ -0,0 +1,64 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// Repository persists provider call counts; it implements weather_api.QuotaStore.
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return Repository{
+		db: db,
+	}
+}
+
+// Take accounts for one request unless quota requests, when it is positive, were already made that day. The
+// check and the increment are a single upsert, conditional on the stored count, so concurrent requests and
+// instances can't overshoot the quota.
+func (r Repository) Take(ctx context.Context, provider, keyID, day string, quota int64) (bool, error) {
+	row := models.ProviderQuota{
+		Provider:  provider,
+		KeyID:     keyID,
+		Day:       day,
+		Calls:     1,
+		UpdatedAt: time.Now(),
+	}
+
+	onConflict := clause.OnConflict{
+		Columns: []clause.Column{{Name: "provider"}, {Name: "key_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"calls":      gorm.Expr("provider_quotas.calls + 1"),
+			"updated_at": row.UpdatedAt,
+		}),
+	}
+	if quota > 0 {
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{gorm.Expr("provider_quotas.calls < ?", quota)}}
+	}
+
+	result := r.db.WithContext(ctx).Clauses(onConflict).Create(&row)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	// a conflicting row left as is, its quota being used up, isn't affected
+	return result.RowsAffected > 0, nil
+}
+
+func (r Repository) Count(ctx context.Context, provider, keyID, day string) (int64, error) {
+	var quota models.ProviderQuota
+
+	err := r.db.WithContext(ctx).Where("provider = ? AND key_id = ? AND day = ?", provider, keyID, day).First(&quota).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+
+	return quota.Calls, err
+}
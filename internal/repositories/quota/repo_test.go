@@ -0,0 +1,78 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.ProviderQuota{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRepository_TakeAndCount(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	t.Run("count without calls", func(t *testing.T) {
+		count, err := repo.Count(ctx, "OpenWeather", "key", "2025-09-01")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("take accumulates per provider, key and day", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			take(t, repo, "OpenWeather", "key", "2025-09-01", 0)
+		}
+		take(t, repo, "OpenWeather", "key", "2025-09-02", 0)
+		take(t, repo, "OpenWeather", "other-key", "2025-09-01", 0)
+		take(t, repo, "OpenMeteo", "key", "2025-09-01", 0)
+
+		count, err := repo.Count(ctx, "OpenWeather", "key", "2025-09-01")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		count, err = repo.Count(ctx, "OpenWeather", "key", "2025-09-02")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		var rows int64
+		db.Model(&models.ProviderQuota{}).Count(&rows)
+		assert.Equal(t, int64(4), rows)
+	})
+
+	t.Run("take stops at the quota", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			taken, err := repo.Take(ctx, "OpenWeather", "quota-key", "2025-09-01", 2)
+			require.NoError(t, err)
+			assert.True(t, taken)
+		}
+
+		taken, err := repo.Take(ctx, "OpenWeather", "quota-key", "2025-09-01", 2)
+		require.NoError(t, err)
+		assert.False(t, taken)
+
+		count, err := repo.Count(ctx, "OpenWeather", "quota-key", "2025-09-01")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func take(t *testing.T, repo Repository, provider, keyID, day string, quota int64) {
+	taken, err := repo.Take(context.Background(), provider, keyID, day, quota)
+	require.NoError(t, err)
+	require.True(t, taken)
+}
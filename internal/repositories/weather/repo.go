@@ -2,13 +2,22 @@ package weather
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/pkg/like"
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/schemata"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-	"math"
+	"gorm.io/gorm/clause"
+	"slices"
+	"strings"
+	"time"
 )
 
+var InvalidCursorErr = errors.New("cursor is invalid")
+
 type Repository struct {
 	db *gorm.DB
 }
@@ -23,35 +32,269 @@ func (r Repository) Create(ctx context.Context, w *models.Weather) error {
 	return r.db.WithContext(ctx).Create(w).Error
 }
 
+// CreateBackfilled inserts backfilled weathers at once, none of them when any fails, but those of a location
+// and observation time already backfilled. It returns how many were inserted.
+func (r Repository) CreateBackfilled(ctx context.Context, weathers []models.Weather) (int64, error) {
+	for i := range weathers {
+		weathers[i].Backfilled = true
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "location_id"}, {Name: "observed_at"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "backfilled"}}},
+		DoNothing:   true,
+	}).Create(&weathers)
+
+	return result.RowsAffected, result.Error
+}
+
 func (r Repository) Update(ctx context.Context, id uuid.UUID, input map[string]interface{}) error {
 	return r.db.WithContext(ctx).Model(&models.Weather{}).Where("id = ?", id).Updates(input).Error
 }
 
-func (r Repository) PaginatedList(ctx context.Context, page int) (weathers []models.Weather, totalPage, count int64, err error) {
-	offset := (page - 1) * schemata.PaginationLimit
+// filtered narrows query down to the weathers matching f, whose ranges are in the units weather is stored in.
+func filtered(query *gorm.DB, f schemata.ListFilter) *gorm.DB {
+	if f.Condition != "" {
+		query = query.Where("condition = ?", f.Condition)
+	}
+	if f.Intensity != "" {
+		query = query.Where("intensity = ?", f.Intensity)
+	}
+	if f.CityName != "" {
+		query = query.Where("LOWER(city_name) = LOWER(?)", f.CityName)
+	}
+	if f.Country != "" {
+		query = query.Where("LOWER(country) = LOWER(?)", f.Country)
+	}
+	if f.Provider != "" {
+		query = query.Where("LOWER(provider) = LOWER(?)", f.Provider)
+	}
+	if f.Description != "" {
+		query = query.Where("LOWER(description) LIKE ? ESCAPE '\\'", "%"+like.Escape(strings.ToLower(f.Description))+"%")
+	}
+
+	query = between(query, "temperature", f.MinTemperature, f.MaxTemperature)
+	query = between(query, "humidity", f.MinHumidity, f.MaxHumidity)
+	query = between(query, "wind_speed", f.MinWindSpeed, f.MaxWindSpeed)
+
+	if f.FetchedFrom != nil {
+		query = query.Where("fetched_at >= ?", *f.FetchedFrom)
+	}
+	if f.FetchedBefore != nil {
+		query = query.Where("fetched_at < ?", *f.FetchedBefore)
+	}
+
+	return query
+}
+
+// between narrows query down to the rows whose column is within low and high, either bound being optional.
+func between[T int | float64](query *gorm.DB, column string, low, high *T) *gorm.DB {
+	if low != nil {
+		query = query.Where(clause.Gte{Column: clause.Column{Name: column}, Value: *low})
+	}
+	if high != nil {
+		query = query.Where(clause.Lte{Column: clause.Column{Name: column}, Value: *high})
+	}
+
+	return query
+}
+
+// PaginatedList returns a page of pageSize weathers matching filter ordered by sort, newest first when
+// it is empty, and ties broken by creation, newest first, like KeysetList orders them. more tells whether
+// weathers are listed after the page; Count counts them all.
+func (r Repository) PaginatedList(ctx context.Context, page, pageSize int, filter schemata.ListFilter, sort []schemata.SortField) (weathers []models.Weather, more bool, err error) {
+	offset := (page - 1) * pageSize
+
+	// raw payloads are only served one at a time
+	query := filtered(r.db.WithContext(ctx).Model(models.Weather{}).Omit("raw"), filter)
+
+	for _, field := range sort {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Field}, Desc: field.Desc})
+	}
+	if !slices.ContainsFunc(sort, func(f schemata.SortField) bool { return f.Field == "created_at" }) {
+		query = query.Order("created_at desc")
+	}
+	query = query.Order("id desc")
+
+	// the weather after the page, if any, tells there are more
+	err = query.Offset(offset).Limit(pageSize + 1).Find(&weathers).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	more = len(weathers) > pageSize
+	if more {
+		weathers = weathers[:pageSize]
+	}
+
+	return weathers, more, nil
+}
+
+// Cursor is the position of a weather in the newest first order, by creation and then id, of KeysetList. A
+// cursor pages through the weathers after it, or before it when Before is set.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Before    bool      `json:"b,omitempty"`
+}
+
+// String encodes c into an opaque token.
+func (c Cursor) String() string {
+	encoded, _ := json.Marshal(c)
 
-	query := r.db.WithContext(ctx).Model(models.Weather{})
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
 
-	query.Count(&count)
+// ParseCursor decodes a token encoded by Cursor.String.
+func ParseCursor(token string) (Cursor, error) {
+	var c Cursor
 
-	result := query.Order("created_at desc").Offset(offset).Limit(schemata.PaginationLimit).Find(&weathers)
-	totalPage = int64(math.Ceil(float64(count) / float64(schemata.PaginationLimit)))
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(decoded, &c)
+	}
+	if err != nil || c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return Cursor{}, InvalidCursorErr
+	}
 
-	return weathers, totalPage, count, result.Error
+	return c, nil
+}
+
+// cursorOf returns the cursor of w paging through the weathers after it, or before it.
+func cursorOf(w models.Weather, before bool) *Cursor {
+	return &Cursor{CreatedAt: w.CreatedAt, ID: w.ID, Before: before}
+}
+
+// KeysetPage is a page of KeysetList with the cursors of its neighbour pages, nil at the ends of the list.
+type KeysetPage struct {
+	Weathers []models.Weather
+	Next     *Cursor
+	Prev     *Cursor
+}
+
+// KeysetList returns the page of pageSize weathers matching filter next to cursor, newest first, or the first page
+// when cursor is nil. Unlike PaginatedList, pages neither skip nor repeat weathers created meanwhile.
+func (r Repository) KeysetList(ctx context.Context, pageSize int, filter schemata.ListFilter, cursor *Cursor) (page KeysetPage, err error) {
+	// raw payloads are only served one at a time
+	query := filtered(r.db.WithContext(ctx).Model(models.Weather{}).Omit("raw"), filter)
+
+	order := "created_at DESC, id DESC"
+	switch {
+	case cursor == nil:
+	case cursor.Before:
+		query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		order = "created_at, id"
+	default:
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	// the weather after the page, if any, tells there is a next page
+	err = query.Order(order).Limit(pageSize + 1).Find(&page.Weathers).Error
+	if err != nil {
+		return page, err
+	}
+
+	more := len(page.Weathers) > pageSize
+	if more {
+		page.Weathers = page.Weathers[:pageSize]
+	}
+
+	if cursor != nil && cursor.Before {
+		slices.Reverse(page.Weathers)
+	}
+
+	if len(page.Weathers) == 0 {
+		return page, nil
+	}
+
+	first, last := page.Weathers[0], page.Weathers[len(page.Weathers)-1]
+
+	// a page reached by cursor always has the page it was reached from on the other side
+	if cursor != nil && cursor.Before {
+		page.Next = cursorOf(last, false)
+		if more {
+			page.Prev = cursorOf(first, true)
+		}
+	} else {
+		if more {
+			page.Next = cursorOf(last, false)
+		}
+		if cursor != nil {
+			page.Prev = cursorOf(first, true)
+		}
+	}
+
+	return page, nil
+}
+
+// Count returns the number of weathers matching filter.
+func (r Repository) Count(ctx context.Context, filter schemata.ListFilter) (count int64, err error) {
+	err = filtered(r.db.WithContext(ctx).Model(models.Weather{}), filter).Count(&count).Error
+
+	return count, err
 }
 
 func (r Repository) LatestByCityName(ctx context.Context, cityName string) (w *models.Weather, err error) {
-	err = r.db.WithContext(ctx).Where("LOWER(city_name) = LOWER(?)", cityName).Order("created_at DESC").First(&w).Error
+	err = r.db.WithContext(ctx).Omit("raw").Preload("Location").Where("LOWER(city_name) = LOWER(?)", cityName).Order("created_at DESC").First(&w).Error
+
+	return w, err
+}
+
+func (r Repository) LatestByLocationID(ctx context.Context, locationID uuid.UUID) (w *models.Weather, err error) {
+	err = r.db.WithContext(ctx).Omit("raw").Preload("Location").Where("location_id = ?", locationID).Order("created_at DESC").First(&w).Error
 
 	return w, err
 }
 
 func (r Repository) FindById(ctx context.Context, id uuid.UUID) (w *models.Weather, err error) {
-	err = r.db.WithContext(ctx).Where("id = ?", id).First(&w).Error
+	err = r.db.WithContext(ctx).Preload("Readings").Preload("Location").Where("id = ?", id).First(&w).Error
 
 	return w, err
 }
 
+// ListRemappable returns up to limit records holding a raw payload and never edited, with their readings,
+// by id from the one after afterID, from the first when it is uuid.Nil.
+func (r Repository) ListRemappable(ctx context.Context, afterID uuid.UUID, limit int) (weathers []models.Weather, err error) {
+	err = r.db.WithContext(ctx).Preload("Readings").Where("raw IS NOT NULL AND edited_at IS NULL").
+		Where("id > ?", afterID).Order("id").Limit(limit).Find(&weathers).Error
+
+	return weathers, err
+}
+
+// SaveRemapped updates every column of w and of its readings unless w was edited since it was read, in
+// which case nothing is saved and false is returned.
+func (r Repository) SaveRemapped(ctx context.Context, w *models.Weather) (saved bool, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(w).Select("*").Omit(clause.Associations, "id", "created_at").
+			Where("edited_at IS NULL").Updates(w)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		for i := range w.Readings {
+			if err := tx.Save(&w.Readings[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		saved = true
+
+		return nil
+	})
+
+	return saved, err
+}
+
+// ObservationTimes returns when the weathers of the location with locationID were observed, from from,
+// inclusive, to to, exclusive.
+func (r Repository) ObservationTimes(ctx context.Context, locationID uuid.UUID, from, to time.Time) (times []time.Time, err error) {
+	err = r.db.WithContext(ctx).Model(models.Weather{}).
+		Where("location_id = ? AND observed_at >= ? AND observed_at < ?", locationID, from, to).
+		Pluck("observed_at", &times).Error
+
+	return times, err
+}
+
 func (r Repository) DeleteById(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(models.Weather{}, id).Error
 }
@@ -2,10 +2,12 @@ package weather
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/AbolfazlAkhtari/weather-forecast/internal/models"
+	"github.com/AbolfazlAkhtari/weather-forecast/internal/schemata"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,7 +19,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&models.Weather{})
+	err = db.AutoMigrate(&models.Weather{}, &models.WeatherReading{})
 	require.NoError(t, err)
 
 	return db
@@ -141,11 +143,10 @@ func TestRepository_PaginatedList(t *testing.T) {
 	}
 
 	t.Run("first page", func(t *testing.T) {
-		results, totalPage, count, err := repo.PaginatedList(ctx, 1)
+		results, more, err := repo.PaginatedList(ctx, 1, 10, schemata.ListFilter{}, nil)
 
 		assert.NoError(t, err)
-		assert.Equal(t, int64(5), count)
-		assert.Equal(t, int64(1), totalPage)
+		assert.False(t, more)
 		assert.Len(t, results, 5)
 		// Should be ordered by created_at desc
 		assert.Equal(t, "Tabriz", results[0].CityName)
@@ -168,38 +169,38 @@ func TestRepository_PaginatedList(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		results, totalPage, count, err := repo.PaginatedList(ctx, 1)
+		results, more, err := repo.PaginatedList(ctx, 1, 10, schemata.ListFilter{}, nil)
 
 		assert.NoError(t, err)
-		assert.Equal(t, int64(20), count)    // 5 original + 15 new
-		assert.Equal(t, int64(2), totalPage) // 20 records / 10 limit = 2 pages
-		assert.Len(t, results, 10)           // First page should have 10 records
+		assert.True(t, more)       // 20 records / 10 limit = 2 pages
+		assert.Len(t, results, 10) // First page should have 10 records
+
+		count, err := repo.Count(ctx, schemata.ListFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), count) // 5 original + 15 new
 	})
 
 	t.Run("second page", func(t *testing.T) {
-		results, totalPage, count, err := repo.PaginatedList(ctx, 2)
+		results, more, err := repo.PaginatedList(ctx, 2, 10, schemata.ListFilter{}, nil)
 
 		assert.NoError(t, err)
-		assert.Equal(t, int64(20), count)
-		assert.Equal(t, int64(2), totalPage)
+		assert.False(t, more)
 		assert.Len(t, results, 10) // Second page should have 10 records
 	})
 
 	t.Run("page beyond available data", func(t *testing.T) {
-		results, totalPage, count, err := repo.PaginatedList(ctx, 5)
+		results, more, err := repo.PaginatedList(ctx, 5, 10, schemata.ListFilter{}, nil)
 
 		assert.NoError(t, err)
-		assert.Equal(t, int64(20), count)
-		assert.Equal(t, int64(2), totalPage)
+		assert.False(t, more)
 		assert.Len(t, results, 0) // No results for page 5
 	})
 
 	t.Run("page 0", func(t *testing.T) {
-		results, totalPage, count, err := repo.PaginatedList(ctx, 0)
+		results, more, err := repo.PaginatedList(ctx, 0, 10, schemata.ListFilter{}, nil)
 
 		assert.NoError(t, err)
-		assert.Equal(t, int64(20), count)
-		assert.Equal(t, int64(2), totalPage)
+		assert.True(t, more)
 		assert.Len(t, results, 10) // Should default to first page
 	})
 }
@@ -332,3 +333,339 @@ func TestRepository_DeleteById(t *testing.T) {
 		assert.NoError(t, err) // GORM doesn't return error for no rows affected
 	})
 }
+
+func TestRepository_ListRemappable(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	withoutRaw := createTestWeather()
+	require.NoError(t, repo.Create(ctx, withoutRaw))
+
+	editedAt := time.Now()
+	edited := createTestWeather()
+	edited.Raw = models.RawPayload(`{"temp":25.5}`)
+	edited.EditedAt = &editedAt
+	require.NoError(t, repo.Create(ctx, edited))
+
+	var withRaw []uuid.UUID
+	for i := 0; i < 3; i++ {
+		weather := createTestWeather()
+		weather.Raw = models.RawPayload(`{"temp":25.5}`)
+		require.NoError(t, repo.Create(ctx, weather))
+
+		withRaw = append(withRaw, weather.ID)
+	}
+
+	first, err := repo.ListRemappable(ctx, uuid.Nil, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	rest, err := repo.ListRemappable(ctx, first[1].ID, 2)
+	require.NoError(t, err)
+
+	var ids []uuid.UUID
+	for _, w := range append(first, rest...) {
+		ids = append(ids, w.ID)
+		assert.JSONEq(t, `{"temp":25.5}`, string(w.Raw))
+	}
+
+	assert.ElementsMatch(t, withRaw, ids, "records without payload or edited are left out")
+}
+
+func TestRepository_SaveRemapped(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	weather := createTestWeather()
+	weather.Readings = []models.WeatherReading{{Provider: "A", Temperature: 20}}
+	require.NoError(t, repo.Create(ctx, weather))
+
+	pressure := 1013
+	weather.Pressure = &pressure
+	weather.Readings[0].Temperature = 21
+
+	saved, err := repo.SaveRemapped(ctx, weather)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	result, err := repo.FindById(ctx, weather.ID)
+	require.NoError(t, err)
+	require.NotNil(t, result.Pressure)
+	assert.Equal(t, pressure, *result.Pressure)
+	require.Len(t, result.Readings, 1, "readings are updated rather than inserted again")
+	assert.Equal(t, 21.0, result.Readings[0].Temperature)
+
+	t.Run("edited since it was read", func(t *testing.T) {
+		require.NoError(t, repo.Update(ctx, weather.ID, map[string]interface{}{"temperature": 30, "edited_at": time.Now()}))
+
+		weather.Temperature = 25
+		weather.Readings[0].Temperature = 22
+
+		saved, err := repo.SaveRemapped(ctx, weather)
+		require.NoError(t, err)
+		assert.False(t, saved)
+
+		result, err := repo.FindById(ctx, weather.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 30.0, result.Temperature, "the edit is kept")
+		assert.Equal(t, 21.0, result.Readings[0].Temperature)
+	})
+}
+
+func TestRepository_PaginatedList_Filter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	weathers := []*models.Weather{
+		{CityName: "Bergen", Country: "NO", Condition: "rain", Intensity: "heavy", Description: "heavy intensity rain", Temperature: 11, Humidity: 95, WindSpeed: 9, Provider: "OpenWeather", FetchedAt: day},
+		{CityName: "Oslo", Country: "NO", Condition: "rain", Intensity: "light", Description: "light rain", Temperature: 14, Humidity: 80, WindSpeed: 3, Provider: "OpenMeteo", FetchedAt: day.Add(26 * time.Hour)},
+		{CityName: "Tromso", Country: "NO", Condition: "snow", Intensity: "heavy", Description: "heavy snow", Temperature: -2, Humidity: 90, WindSpeed: 12, Provider: "OpenWeather", FetchedAt: day.Add(50 * time.Hour)},
+		{CityName: "Stavanger", Country: "NO", Condition: "clear", Description: "100% clear_sky", Temperature: 16, Humidity: 60, WindSpeed: 5, Provider: "OpenWeather", FetchedAt: day.Add(74 * time.Hour)},
+	}
+	for _, w := range weathers {
+		require.NoError(t, repo.Create(ctx, w))
+	}
+
+	tests := []struct {
+		name     string
+		filter   schemata.ListFilter
+		expected []string
+	}{
+		{name: "no filter", filter: schemata.ListFilter{}, expected: []string{"Bergen", "Oslo", "Tromso", "Stavanger"}},
+		{name: "condition", filter: schemata.ListFilter{Condition: "rain"}, expected: []string{"Bergen", "Oslo"}},
+		{name: "intensity", filter: schemata.ListFilter{Intensity: "heavy"}, expected: []string{"Bergen", "Tromso"}},
+		{name: "condition and intensity", filter: schemata.ListFilter{Condition: "rain", Intensity: "heavy"}, expected: []string{"Bergen"}},
+		{name: "no match", filter: schemata.ListFilter{Condition: "fog"}, expected: nil},
+		{name: "city ignoring case", filter: schemata.ListFilter{CityName: "oslo"}, expected: []string{"Oslo"}},
+		{name: "country and provider", filter: schemata.ListFilter{Country: "no", Provider: "openmeteo"}, expected: []string{"Oslo"}},
+		{name: "description substring", filter: schemata.ListFilter{Description: "RAIN"}, expected: []string{"Bergen", "Oslo"}},
+		{name: "description wildcards are literal", filter: schemata.ListFilter{Description: "_"}, expected: []string{"Stavanger"}},
+		{name: "temperature range", filter: schemata.ListFilter{MinTemperature: ptr(11.0), MaxTemperature: ptr(14.0)}, expected: []string{"Bergen", "Oslo"}},
+		{name: "humidity above", filter: schemata.ListFilter{MinHumidity: ptr(90)}, expected: []string{"Bergen", "Tromso"}},
+		{name: "wind speed below", filter: schemata.ListFilter{MaxWindSpeed: ptr(5.0)}, expected: []string{"Oslo", "Stavanger"}},
+		{name: "fetched range", filter: schemata.ListFilter{FetchedFrom: ptr(day.Add(24 * time.Hour)), FetchedBefore: ptr(day.Add(72 * time.Hour))}, expected: []string{"Oslo", "Tromso"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, _, err := repo.PaginatedList(ctx, 1, 10, tt.filter, nil)
+			require.NoError(t, err)
+
+			count, err := repo.Count(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.expected)), count)
+
+			var names []string
+			for _, w := range results {
+				names = append(names, w.CityName)
+			}
+			assert.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
+
+func TestRepository_PaginatedList_Sort(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	weathers := []*models.Weather{
+		{CityName: "Bergen", Temperature: 11, Humidity: 95, FetchedAt: now, CreatedAt: now.Add(-3 * time.Minute)},
+		{CityName: "Oslo", Temperature: 14, Humidity: 80, FetchedAt: now, CreatedAt: now.Add(-2 * time.Minute)},
+		{CityName: "Tromso", Temperature: -2, Humidity: 80, FetchedAt: now, CreatedAt: now.Add(-time.Minute)},
+	}
+	for _, w := range weathers {
+		require.NoError(t, repo.Create(ctx, w))
+	}
+
+	tests := []struct {
+		name     string
+		sort     []schemata.SortField
+		expected []string
+	}{
+		{name: "newest first by default", expected: []string{"Tromso", "Oslo", "Bergen"}},
+		{name: "ascending", sort: []schemata.SortField{{Field: "temperature"}}, expected: []string{"Tromso", "Bergen", "Oslo"}},
+		{name: "descending", sort: []schemata.SortField{{Field: "city_name", Desc: true}}, expected: []string{"Tromso", "Oslo", "Bergen"}},
+		{name: "ties broken by the next field", sort: []schemata.SortField{{Field: "humidity"}, {Field: "temperature", Desc: true}}, expected: []string{"Oslo", "Tromso", "Bergen"}},
+		{name: "ties broken by creation", sort: []schemata.SortField{{Field: "humidity"}}, expected: []string{"Tromso", "Oslo", "Bergen"}},
+		{name: "oldest first", sort: []schemata.SortField{{Field: "created_at"}}, expected: []string{"Bergen", "Oslo", "Tromso"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, _, err := repo.PaginatedList(ctx, 1, 10, schemata.ListFilter{}, tt.sort)
+
+			require.NoError(t, err)
+
+			var names []string
+			for _, w := range results {
+				names = append(names, w.CityName)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestRepository_KeysetList(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	// every other weather shares its creation time with the previous one, ties being broken by id
+	start := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 25; i++ {
+		w := &models.Weather{CityName: fmt.Sprintf("City%02d", i), Country: "NO", FetchedAt: start, CreatedAt: start.Add(time.Duration(i/2) * time.Minute)}
+		require.NoError(t, repo.Create(ctx, w))
+	}
+
+	var ordered []models.Weather
+	require.NoError(t, db.Order("created_at desc, id desc").Find(&ordered).Error)
+
+	ids := func(weathers []models.Weather) []uuid.UUID {
+		ids := make([]uuid.UUID, 0, len(weathers))
+		for _, w := range weathers {
+			ids = append(ids, w.ID)
+		}
+
+		return ids
+	}
+
+	first, err := repo.KeysetList(ctx, 10, schemata.ListFilter{}, nil)
+	require.NoError(t, err)
+
+	t.Run("first page", func(t *testing.T) {
+		assert.Equal(t, ids(ordered[:10]), ids(first.Weathers))
+		assert.Nil(t, first.Prev)
+		require.NotNil(t, first.Next)
+	})
+
+	second, err := repo.KeysetList(ctx, 10, schemata.ListFilter{}, first.Next)
+	require.NoError(t, err)
+
+	t.Run("next pages", func(t *testing.T) {
+		assert.Equal(t, ids(ordered[10:20]), ids(second.Weathers))
+		require.NotNil(t, second.Prev)
+		require.NotNil(t, second.Next)
+
+		last, err := repo.KeysetList(ctx, 10, schemata.ListFilter{}, second.Next)
+		require.NoError(t, err)
+		assert.Equal(t, ids(ordered[20:]), ids(last.Weathers))
+		assert.Nil(t, last.Next)
+		require.NotNil(t, last.Prev)
+
+		previous, err := repo.KeysetList(ctx, 10, schemata.ListFilter{}, last.Prev)
+		require.NoError(t, err)
+		assert.Equal(t, ids(second.Weathers), ids(previous.Weathers))
+	})
+
+	t.Run("previous page", func(t *testing.T) {
+		previous, err := repo.KeysetList(ctx, 10, schemata.ListFilter{}, second.Prev)
+
+		require.NoError(t, err)
+		assert.Equal(t, ids(first.Weathers), ids(previous.Weathers))
+		assert.Nil(t, previous.Prev)
+		require.NotNil(t, previous.Next)
+	})
+
+	t.Run("weathers created meanwhile don't shift pages", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, &models.Weather{CityName: "Newest", Country: "NO", FetchedAt: start, CreatedAt: start.Add(time.Hour)}))
+
+		again, err := repo.KeysetList(ctx, 10, schemata.ListFilter{}, first.Next)
+
+		require.NoError(t, err)
+		assert.Equal(t, ids(second.Weathers), ids(again.Weathers))
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		page, err := repo.KeysetList(ctx, 10, schemata.ListFilter{CityName: "city03"}, nil)
+
+		require.NoError(t, err)
+		require.Len(t, page.Weathers, 1)
+		assert.Nil(t, page.Next)
+
+		count, err := repo.Count(ctx, schemata.ListFilter{Country: "no"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(26), count)
+	})
+
+	t.Run("cursor tokens", func(t *testing.T) {
+		parsed, err := ParseCursor(second.Prev.String())
+
+		require.NoError(t, err)
+		assert.True(t, second.Prev.CreatedAt.Equal(parsed.CreatedAt))
+		assert.Equal(t, second.Prev.ID, parsed.ID)
+		assert.True(t, parsed.Before)
+
+		previous, err := repo.KeysetList(ctx, 10, schemata.ListFilter{}, &parsed)
+		require.NoError(t, err)
+		assert.Equal(t, ids(first.Weathers), ids(previous.Weathers))
+
+		for _, token := range []string{"", "not-base64!", "bm90LWpzb24", "e30"} {
+			_, err := ParseCursor(token)
+			assert.ErrorIs(t, err, InvalidCursorErr, token)
+		}
+	})
+}
+
+func TestRepository_ObservationTimes(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	locationID, otherID := uuid.New(), uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var weathers []models.Weather
+	for i := 0; i < 4; i++ {
+		observedAt := start.Add(time.Duration(i) * time.Hour)
+		weathers = append(weathers, models.Weather{CityName: "Paris", LocationID: &locationID, ObservedAt: &observedAt, FetchedAt: observedAt})
+	}
+	other := start.Add(time.Hour)
+	weathers = append(weathers, models.Weather{CityName: "Lyon", LocationID: &otherID, ObservedAt: &other, FetchedAt: other})
+
+	created, err := repo.CreateBackfilled(ctx, weathers)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), created)
+
+	times, err := repo.ObservationTimes(ctx, locationID, start.Add(time.Hour), start.Add(3*time.Hour))
+
+	require.NoError(t, err)
+	require.Len(t, times, 2, "from is included and to excluded")
+	assert.ElementsMatch(t, []int64{start.Add(time.Hour).Unix(), start.Add(2 * time.Hour).Unix()}, []int64{times[0].Unix(), times[1].Unix()})
+}
+
+func TestRepository_CreateBackfilled(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	locationID := uuid.New()
+	observedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := observedAt.Add(time.Hour)
+
+	current := models.Weather{CityName: "Paris", LocationID: &locationID, ObservedAt: &observedAt, FetchedAt: observedAt}
+	require.NoError(t, repo.Create(ctx, &current))
+
+	created, err := repo.CreateBackfilled(ctx, []models.Weather{{CityName: "Paris", LocationID: &locationID, ObservedAt: &observedAt, FetchedAt: observedAt}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created, "weathers fetched as current don't conflict")
+
+	created, err = repo.CreateBackfilled(ctx, []models.Weather{
+		{CityName: "Paris", LocationID: &locationID, ObservedAt: &observedAt, FetchedAt: observedAt},
+		{CityName: "Paris", LocationID: &locationID, ObservedAt: &next, FetchedAt: next},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created, "an observation is backfilled once")
+
+	var count int64
+	require.NoError(t, db.Model(&models.Weather{}).Where("backfilled").Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
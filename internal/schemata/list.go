@@ -0,0 +1,36 @@
+package schemata
+
+import "time"
+
+// ListFilter narrows the weathers listed down to the ones matching all its non-empty fields.
+type ListFilter struct {
+	Condition string
+	Intensity string
+	// CityName, Country and Provider match ignoring case
+	CityName string
+	Country  string
+	Provider string
+	// Description matches the descriptions containing it, ignoring case
+	Description string
+	// the ranges below are inclusive
+	MinTemperature *float64
+	MaxTemperature *float64
+	MinHumidity    *int
+	MaxHumidity    *int
+	MinWindSpeed   *float64
+	MaxWindSpeed   *float64
+	// FetchedFrom and FetchedBefore bound the fetch time, FetchedBefore exclusively
+	FetchedFrom   *time.Time
+	FetchedBefore *time.Time
+}
+
+// SortFields are the fields the weathers can be listed by.
+var SortFields = []string{
+	"created_at", "fetched_at", "observed_at", "city_name", "country", "provider", "temperature", "humidity", "wind_speed",
+}
+
+// SortField orders the weathers listed by Field, one of SortFields, descending when Desc is set.
+type SortField struct {
+	Field string
+	Desc  bool
+}
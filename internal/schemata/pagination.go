@@ -1,9 +1,26 @@
 package schemata
 
-const PaginationLimit = 10
-
 type Pagination struct {
-	TotalPage   int64 `json:"total_page"`
-	TotalCount  int64 `json:"total_count"`
-	CurrentPage int   `json:"current_page"`
+	// TotalPage and TotalCount are only set when the weathers were counted
+	TotalPage   *int64 `json:"total_page,omitempty"`
+	TotalCount  *int64 `json:"total_count,omitempty"`
+	CurrentPage int    `json:"current_page"`
+	// PageSize is the most weathers a page lists
+	PageSize int `json:"page_size"`
+	// Next and Prev link the neighbour pages by cursor, empty at the ends of the list and when it is sorted otherwise
+	// than newest first
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// CursorPagination paginates the pages reached by cursor, which have no number.
+type CursorPagination struct {
+	// TotalPage and TotalCount are only set when the weathers were counted
+	TotalPage  *int64 `json:"total_page,omitempty"`
+	TotalCount *int64 `json:"total_count,omitempty"`
+	// PageSize is the most weathers a page lists
+	PageSize int `json:"page_size"`
+	// Next and Prev link the neighbour pages by cursor, empty at the ends of the list
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 }
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE weathers
+    ADD COLUMN provider VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS provider;
+-- +goose StatementEnd
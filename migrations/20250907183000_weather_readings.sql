@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE weathers
+    ADD COLUMN disagreements TEXT;
+
+CREATE TABLE weather_readings
+(
+    id          UUID PRIMARY KEY,
+    weather_id  UUID             NOT NULL REFERENCES weathers (id) ON DELETE CASCADE,
+    provider    VARCHAR(255)     NOT NULL,
+    temperature DOUBLE PRECISION NOT NULL,
+    description VARCHAR(255),
+    humidity    INT              NOT NULL,
+    wind_speed  DOUBLE PRECISION NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX weather_readings_weather_id_index ON weather_readings (weather_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS weather_readings;
+
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS disagreements;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE provider_quotas
+(
+    provider   VARCHAR(255) NOT NULL,
+    key_id     VARCHAR(64)  NOT NULL,
+    day        VARCHAR(10)  NOT NULL,
+    calls      BIGINT       NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, key_id, day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS provider_quotas;
+-- +goose StatementEnd
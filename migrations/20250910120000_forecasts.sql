@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE forecasts
+(
+    id         UUID PRIMARY KEY,
+    city_name  VARCHAR(255) NOT NULL,
+    country    VARCHAR(255) NOT NULL,
+    provider   VARCHAR(255),
+    entries    TEXT         NOT NULL,
+    fetched_at TIMESTAMP    NOT NULL,
+    expires_at TIMESTAMP    NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX forecasts_city_name_index ON forecasts (LOWER(city_name), created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS forecasts;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE locations
+(
+    id           UUID PRIMARY KEY,
+    name         VARCHAR(255)     NOT NULL,
+    country_code VARCHAR(8)       NOT NULL,
+    state        VARCHAR(255),
+    latitude     DOUBLE PRECISION NOT NULL,
+    longitude    DOUBLE PRECISION NOT NULL,
+    timezone     VARCHAR(64),
+    aliases      TEXT,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX locations_name_index ON locations (LOWER(name));
+CREATE INDEX locations_coordinates_index ON locations (country_code, latitude, longitude);
+
+ALTER TABLE weathers
+    ADD COLUMN location_id UUID REFERENCES locations (id) ON DELETE SET NULL;
+
+CREATE INDEX weathers_location_id_index ON weathers (location_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS location_id;
+
+DROP TABLE IF EXISTS locations;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE weathers
+    ADD COLUMN pressure    INT,
+    ADD COLUMN wind_deg    INT,
+    ADD COLUMN wind_gust   DOUBLE PRECISION,
+    ADD COLUMN feels_like  DOUBLE PRECISION,
+    ADD COLUMN temp_min    DOUBLE PRECISION,
+    ADD COLUMN temp_max    DOUBLE PRECISION,
+    ADD COLUMN visibility  INT,
+    ADD COLUMN clouds      INT,
+    ADD COLUMN rain_1h     DOUBLE PRECISION,
+    ADD COLUMN rain_3h     DOUBLE PRECISION,
+    ADD COLUMN snow_1h     DOUBLE PRECISION,
+    ADD COLUMN snow_3h     DOUBLE PRECISION,
+    ADD COLUMN sunrise     TIMESTAMP,
+    ADD COLUMN sunset      TIMESTAMP,
+    ADD COLUMN observed_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS pressure,
+    DROP COLUMN IF EXISTS wind_deg,
+    DROP COLUMN IF EXISTS wind_gust,
+    DROP COLUMN IF EXISTS feels_like,
+    DROP COLUMN IF EXISTS temp_min,
+    DROP COLUMN IF EXISTS temp_max,
+    DROP COLUMN IF EXISTS visibility,
+    DROP COLUMN IF EXISTS clouds,
+    DROP COLUMN IF EXISTS rain_1h,
+    DROP COLUMN IF EXISTS rain_3h,
+    DROP COLUMN IF EXISTS snow_1h,
+    DROP COLUMN IF EXISTS snow_3h,
+    DROP COLUMN IF EXISTS sunrise,
+    DROP COLUMN IF EXISTS sunset,
+    DROP COLUMN IF EXISTS observed_at;
+-- +goose StatementEnd
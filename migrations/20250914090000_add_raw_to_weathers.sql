@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE weathers
+    ADD COLUMN raw JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS raw;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE weathers
+    ADD COLUMN condition_code INT;
+ALTER TABLE weather_readings
+    ADD COLUMN condition_code INT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS condition_code;
+ALTER TABLE weather_readings
+    DROP COLUMN IF EXISTS condition_code;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE weathers
+    ADD COLUMN condition VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN intensity VARCHAR(32) NOT NULL DEFAULT '';
+CREATE INDEX weathers_condition_index ON weathers (condition, intensity, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS weathers_condition_index;
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS condition,
+    DROP COLUMN IF EXISTS intensity;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE air_qualities
+(
+    id          UUID PRIMARY KEY,
+    city_name   VARCHAR(255)     NOT NULL,
+    country     VARCHAR(255)     NOT NULL,
+    latitude    DOUBLE PRECISION NOT NULL,
+    longitude   DOUBLE PRECISION NOT NULL,
+    aqi         INTEGER          NOT NULL,
+    co          DOUBLE PRECISION,
+    no          DOUBLE PRECISION,
+    no2         DOUBLE PRECISION,
+    o3          DOUBLE PRECISION,
+    so2         DOUBLE PRECISION,
+    pm2_5       DOUBLE PRECISION,
+    pm10        DOUBLE PRECISION,
+    nh3         DOUBLE PRECISION,
+    observed_at TIMESTAMP,
+    provider    VARCHAR(255),
+    location_id UUID REFERENCES locations (id) ON DELETE SET NULL,
+    fetched_at  TIMESTAMP        NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX air_qualities_city_name_index ON air_qualities (LOWER(city_name), created_at DESC);
+CREATE INDEX air_qualities_location_id_index ON air_qualities (location_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS air_qualities;
+-- +goose StatementEnd
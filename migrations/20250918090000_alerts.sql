@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE alerts
+(
+    id          UUID PRIMARY KEY,
+    city_name   VARCHAR(255)     NOT NULL,
+    country     VARCHAR(255)     NOT NULL,
+    latitude    DOUBLE PRECISION NOT NULL,
+    longitude   DOUBLE PRECISION NOT NULL,
+    provider    VARCHAR(255),
+    sender      VARCHAR(255)     NOT NULL,
+    event       VARCHAR(255)     NOT NULL,
+    severity    VARCHAR(16)      NOT NULL,
+    description TEXT,
+    tags        TEXT,
+    starts_at   TIMESTAMP        NOT NULL,
+    ends_at     TIMESTAMP        NOT NULL,
+    location_id UUID REFERENCES locations (id) ON DELETE SET NULL,
+    fetched_at  TIMESTAMP        NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX alerts_city_name_index ON alerts (LOWER(city_name), ends_at);
+CREATE INDEX alerts_location_id_index ON alerts (location_id, ends_at);
+CREATE INDEX alerts_period_index ON alerts (ends_at, starts_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS alerts;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE forecasts
+    ADD COLUMN location_id UUID REFERENCES locations (id) ON DELETE SET NULL;
+
+CREATE INDEX forecasts_location_id_index ON forecasts (location_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS forecasts_location_id_index;
+
+ALTER TABLE forecasts
+    DROP COLUMN IF EXISTS location_id;
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- this migration is irreversible: the merge below deletes the duplicate locations and repoints their rows, which
+-- Down can't restore. back the locations table up before running it if the merge may need to be undone.
+-- +goose Up
+-- +goose StatementBegin
+-- locations stored more than once for the same place are merged into the oldest of them
+CREATE TEMPORARY TABLE location_duplicates ON COMMIT DROP AS
+SELECT id, keeper_id
+FROM (SELECT id,
+             FIRST_VALUE(id) OVER (PARTITION BY country_code, latitude, longitude ORDER BY created_at, id) AS keeper_id
+      FROM locations) ranked
+WHERE id <> keeper_id;
+
+UPDATE locations
+SET aliases = (SELECT JSON_AGG(DISTINCT alias)::TEXT
+               FROM locations named,
+                    JSON_ARRAY_ELEMENTS_TEXT(named.aliases::JSON) alias
+               WHERE named.aliases LIKE '[%'
+                 AND (named.id = locations.id OR named.id IN (SELECT id FROM location_duplicates WHERE keeper_id = locations.id)))
+WHERE id IN (SELECT keeper_id FROM location_duplicates);
+
+UPDATE weathers
+SET location_id = location_duplicates.keeper_id
+FROM location_duplicates
+WHERE weathers.location_id = location_duplicates.id;
+
+UPDATE forecasts
+SET location_id = location_duplicates.keeper_id
+FROM location_duplicates
+WHERE forecasts.location_id = location_duplicates.id;
+
+UPDATE air_qualities
+SET location_id = location_duplicates.keeper_id
+FROM location_duplicates
+WHERE air_qualities.location_id = location_duplicates.id;
+
+UPDATE alerts
+SET location_id = location_duplicates.keeper_id
+FROM location_duplicates
+WHERE alerts.location_id = location_duplicates.id;
+
+DELETE FROM locations
+WHERE id IN (SELECT id FROM location_duplicates);
+
+DROP INDEX IF EXISTS locations_coordinates_index;
+CREATE UNIQUE INDEX locations_coordinates_index ON locations (country_code, latitude, longitude);
+
+-- weathers fetched before locations existed are attached to the location known by their city name, if any
+UPDATE weathers
+SET location_id = (SELECT locations.id
+                   FROM locations
+                   WHERE UPPER(locations.country_code) = UPPER(weathers.country)
+                     AND (LOWER(locations.name) = LOWER(weathers.city_name)
+                       OR POSITION(TO_JSON(LOWER(weathers.city_name))::TEXT IN locations.aliases) > 0)
+                   ORDER BY locations.created_at
+                   LIMIT 1)
+WHERE location_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- only the index is restored: merged locations stay deleted and their rows stay attached to the kept location
+DROP INDEX IF EXISTS locations_coordinates_index;
+CREATE INDEX locations_coordinates_index ON locations (country_code, latitude, longitude);
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE weathers
+    ADD COLUMN backfilled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- only the backfill command flags the weathers it stores, existing ones are left unflagged
+CREATE UNIQUE INDEX weathers_backfilled_index ON weathers (location_id, observed_at) WHERE backfilled;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS weathers_backfilled_index;
+
+ALTER TABLE weathers
+    DROP COLUMN IF EXISTS backfilled;
+-- +goose StatementEnd
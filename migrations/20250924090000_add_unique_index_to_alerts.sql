@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the unique index below compares providers like the other columns, so alerts stored without one get an empty one
+UPDATE alerts
+SET provider = ''
+WHERE provider IS NULL;
+
+ALTER TABLE alerts
+    ALTER COLUMN provider SET NOT NULL;
+
+-- alerts stored twice by concurrent fetches are kept once, the last fetched
+DELETE
+FROM alerts
+    USING alerts kept
+WHERE alerts.city_name = kept.city_name
+  AND alerts.country = kept.country
+  AND alerts.provider = kept.provider
+  AND alerts.sender = kept.sender
+  AND alerts.event = kept.event
+  AND alerts.starts_at = kept.starts_at
+  AND (alerts.fetched_at, alerts.id) < (kept.fetched_at, kept.id);
+
+CREATE UNIQUE INDEX alerts_identity_index ON alerts (city_name, country, provider, sender, event, starts_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS alerts_identity_index;
+
+ALTER TABLE alerts
+    ALTER COLUMN provider DROP NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Status tells whether a value was served from the cache or loaded for the call
+type Status string
+
+const (
+	Hit  Status = "HIT"
+	Miss Status = "MISS"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache is an in-process TTL cache coalescing concurrent loads of the same key.
+// A zero or negative TTL stores nothing but still coalesces concurrent loads.
+type Cache[V any] struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	entries   map[string]entry[V]
+	lastSweep time.Time
+	group     singleflight.Group
+	now       func() time.Time
+}
+
+func New[V any](ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		ttl:       ttl,
+		entries:   make(map[string]entry[V]),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Get returns the value cached under key if it has not expired.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		delete(c.entries, key)
+
+		var zero V
+		return zero, false
+	}
+
+	return e.value, true
+}
+
+// Set caches value under key for the cache TTL.
+func (c *Cache[V]) Set(key string, value V) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+
+	// keys are never read again once expired, so drop them every TTL to bound memory
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// DeleteFunc drops every cached value matching del.
+func (c *Cache[V]) DeleteFunc(del func(V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, e := range c.entries {
+		if del(e.value) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Fetch returns the value cached under key, or calls load and caches its result.
+// Concurrent calls for the same key share a single load, and all but the caller
+// running it get Hit. Errors are not cached. load runs without the cancellation of
+// ctx so a caller going away doesn't fail the others waiting on it; ctx only bounds
+// how long this caller waits.
+func (c *Cache[V]) Fetch(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, Status, error) {
+	if value, ok := c.Get(key); ok {
+		return value, Hit, nil
+	}
+
+	loaded := false
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		// another caller may have stored it between Get and DoChan
+		if value, ok := c.Get(key); ok {
+			return value, nil
+		}
+
+		loaded = true
+
+		value, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		c.Set(key, value)
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero V
+		return zero, Miss, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			var zero V
+			return zero, Miss, r.Err
+		}
+
+		status := Hit
+		if loaded {
+			status = Miss
+		}
+
+		return r.Val.(V), status, nil
+	}
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(ttl time.Duration) (*Cache[string], *time.Time) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	c := New[string](ttl)
+	c.lastSweep = now
+	c.now = func() time.Time { return now }
+
+	return c, &now
+}
+
+func TestCache_GetSet(t *testing.T) {
+	c, now := newTestCache(time.Minute)
+
+	_, ok := c.Get("key")
+	assert.False(t, ok)
+
+	c.Set("key", "value")
+
+	value, ok := c.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	*now = now.Add(time.Minute)
+
+	_, ok = c.Get("key")
+	assert.False(t, ok, "entry should expire after the TTL")
+}
+
+func TestCache_SetSweepsExpiredEntries(t *testing.T) {
+	c, now := newTestCache(time.Minute)
+
+	c.Set("old", "value")
+	*now = now.Add(2 * time.Minute)
+	c.Set("new", "value")
+
+	assert.Len(t, c.entries, 1)
+	assert.Contains(t, c.entries, "new")
+}
+
+func TestCache_DeleteFunc(t *testing.T) {
+	c, _ := newTestCache(time.Minute)
+	c.Set("a", "keep")
+	c.Set("b", "drop")
+
+	c.DeleteFunc(func(value string) bool { return value == "drop" })
+
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+}
+
+func TestCache_Fetch(t *testing.T) {
+	t.Run("loads on miss then serves from the cache", func(t *testing.T) {
+		c, _ := newTestCache(time.Minute)
+		loads := 0
+		load := func(ctx context.Context) (string, error) {
+			loads++
+			return "value", nil
+		}
+
+		value, status, err := c.Fetch(context.Background(), "key", load)
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+		assert.Equal(t, Miss, status)
+
+		value, status, err = c.Fetch(context.Background(), "key", load)
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+		assert.Equal(t, Hit, status)
+		assert.Equal(t, 1, loads)
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		c, _ := newTestCache(time.Minute)
+		loads := 0
+		load := func(ctx context.Context) (string, error) {
+			loads++
+			return "", errors.New("failed")
+		}
+
+		_, _, err := c.Fetch(context.Background(), "key", load)
+		assert.EqualError(t, err, "failed")
+		_, _, err = c.Fetch(context.Background(), "key", load)
+		assert.EqualError(t, err, "failed")
+		assert.Equal(t, 2, loads)
+	})
+
+	t.Run("zero TTL stores nothing", func(t *testing.T) {
+		c, _ := newTestCache(0)
+		load := func(ctx context.Context) (string, error) { return "value", nil }
+
+		_, _, err := c.Fetch(context.Background(), "key", load)
+		require.NoError(t, err)
+
+		_, status, err := c.Fetch(context.Background(), "key", load)
+		require.NoError(t, err)
+		assert.Equal(t, Miss, status)
+	})
+
+	t.Run("coalesces concurrent loads of the same key", func(t *testing.T) {
+		c := New[string](time.Minute)
+		release := make(chan struct{})
+		var loads atomic.Int32
+		load := func(ctx context.Context) (string, error) {
+			loads.Add(1)
+			<-release
+			return "value", nil
+		}
+
+		const callers = 10
+		statuses := make([]Status, callers)
+
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				value, status, err := c.Fetch(context.Background(), "key", load)
+				assert.NoError(t, err)
+				assert.Equal(t, "value", value)
+				statuses[i] = status
+			}(i)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), loads.Load())
+
+		misses := 0
+		for _, status := range statuses {
+			if status == Miss {
+				misses++
+			}
+		}
+		assert.Equal(t, 1, misses)
+	})
+
+	t.Run("a caller going away doesn't cancel the load", func(t *testing.T) {
+		c := New[string](time.Minute)
+		release := make(chan struct{})
+		load := func(ctx context.Context) (string, error) {
+			<-release
+			return "value", ctx.Err()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := c.Fetch(ctx, "key", load)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+
+		value, _, err := c.Fetch(context.Background(), "key", load)
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+}
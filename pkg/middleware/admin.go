@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/httpres"
+)
+
+// AdminOnly lets through the requests bearing token in their Authorization header and answers 401 to the others.
+// Every request is refused when token is empty, so that admin routes are closed unless a token is configured.
+func AdminOnly(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				httpres.SendResponse(w, http.StatusUnauthorized, nil, nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
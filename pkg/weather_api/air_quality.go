@@ -0,0 +1,20 @@
+package weather_api
+
+import (
+	"context"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// AirQualityReporter is implemented by providers with the schemata.AirQualityCapability.
+type AirQualityReporter interface {
+	FetchAirQuality(ctx context.Context, location schemata.Location) (*schemata.FetchAirQualityResponse, error)
+}
+
+// FetchAirQuality returns the air quality of the first provider of the chain reporting it that answers.
+// Providers without the air quality capability are skipped.
+func (c Chain) FetchAirQuality(ctx context.Context, location schemata.Location) (*schemata.FetchAirQualityResponse, error) {
+	return fetchServed(ctx, c, schemata.AirQualityCapability, func(ctx context.Context, reporter AirQualityReporter) (*schemata.FetchAirQualityResponse, error) {
+		return reporter.FetchAirQuality(ctx, location)
+	})
+}
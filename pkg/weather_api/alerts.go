@@ -0,0 +1,20 @@
+package weather_api
+
+import (
+	"context"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// Alerter is implemented by providers with the schemata.AlertsCapability.
+type Alerter interface {
+	FetchAlerts(ctx context.Context, location schemata.Location) (*schemata.FetchAlertsResponse, error)
+}
+
+// FetchAlerts returns the alerts of the first provider of the chain issuing them that answers.
+// Providers without the alerts capability are skipped.
+func (c Chain) FetchAlerts(ctx context.Context, location schemata.Location) (*schemata.FetchAlertsResponse, error) {
+	return fetchServed(ctx, c, schemata.AlertsCapability, func(ctx context.Context, alerter Alerter) (*schemata.FetchAlertsResponse, error) {
+		return alerter.FetchAlerts(ctx, location)
+	})
+}
@@ -0,0 +1,151 @@
+package weather_api
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+var CircuitOpenErr = errors.New("circuit-open")
+
+// CircuitOpenError is returned instead of calling a provider whose circuit is open.
+// It matches CircuitOpenErr with errors.Is.
+type CircuitOpenError struct {
+	Provider string
+	// RetryAfter is the time left until the breaker lets a probe call through
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return CircuitOpenErr.Error()
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == CircuitOpenErr
+}
+
+// CircuitBreaker short-circuits calls to a provider after FailureThreshold consecutive
+// transient failures. Once OpenDuration elapsed a single probe call is let through
+// (half-open): its success closes the circuit again, its failure re-opens it.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	name     string
+	config   conf.CircuitBreakerConfig
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	now func() time.Time
+}
+
+func NewCircuitBreaker(name string, config conf.CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:   name,
+		config: config,
+		state:  CircuitClosed,
+		now:    time.Now,
+	}
+}
+
+// Allow reports whether a call may go through, returning a *CircuitOpenError otherwise.
+// Every allowed call must be followed by a call to Record.
+func (b *CircuitBreaker) Allow() error {
+	if b == nil || b.config.FailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case CircuitOpen:
+		return &CircuitOpenError{Provider: b.name, RetryAfter: b.openedAt.Add(b.config.OpenDuration).Sub(b.now())}
+	case CircuitHalfOpen:
+		if b.probing {
+			return &CircuitOpenError{Provider: b.name}
+		}
+
+		b.probing = true
+	}
+
+	return nil
+}
+
+// Record reports the outcome of an allowed call. Only transient failures count
+// against the provider; definitive answers are successful calls.
+func (b *CircuitBreaker) Record(transientFailure bool) {
+	if b == nil || b.config.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.currentState()
+	b.probing = false
+
+	if !transientFailure {
+		b.failures = 0
+		b.transition(CircuitClosed)
+		return
+	}
+
+	b.failures++
+	if state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = b.now()
+		b.transition(CircuitOpen)
+	}
+}
+
+// Release ends an allowed call without recording an outcome, e.g. when the caller gave up on it.
+func (b *CircuitBreaker) Release() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	if b == nil || b.config.FailureThreshold <= 0 {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.currentState()
+}
+
+// currentState moves an open circuit to half-open once OpenDuration elapsed. b.mu must be held.
+func (b *CircuitBreaker) currentState() CircuitState {
+	if b.state == CircuitOpen && !b.now().Before(b.openedAt.Add(b.config.OpenDuration)) {
+		b.transition(CircuitHalfOpen)
+	}
+
+	return b.state
+}
+
+func (b *CircuitBreaker) transition(state CircuitState) {
+	if b.state == state {
+		return
+	}
+
+	log.Printf("weather provider %v circuit %v -> %v", b.name, b.state, state)
+	b.state = state
+}
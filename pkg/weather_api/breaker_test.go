@@ -0,0 +1,154 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var testBreakerConfig = conf.CircuitBreakerConfig{
+	FailureThreshold: 2,
+	OpenDuration:     time.Minute,
+}
+
+func newTestBreaker() (*CircuitBreaker, *time.Time) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	breaker := NewCircuitBreaker("Fake", testBreakerConfig)
+	breaker.now = func() time.Time { return now }
+
+	return breaker, &now
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("opens after consecutive transient failures", func(t *testing.T) {
+		breaker, _ := newTestBreaker()
+
+		for i := 0; i < testBreakerConfig.FailureThreshold; i++ {
+			if err := breaker.Allow(); err != nil {
+				t.Fatalf("Allow() unexpected error = %v", err)
+			}
+			breaker.Record(true)
+		}
+
+		if breaker.State() != CircuitOpen {
+			t.Fatalf("State() = %v, want %v", breaker.State(), CircuitOpen)
+		}
+
+		err := breaker.Allow()
+		var openErr *CircuitOpenError
+		if !errors.As(err, &openErr) || !errors.Is(err, CircuitOpenErr) {
+			t.Fatalf("Allow() error = %v, want %v", err, CircuitOpenErr)
+		}
+		if openErr.RetryAfter != time.Minute {
+			t.Errorf("CircuitOpenError.RetryAfter = %v, want %v", openErr.RetryAfter, time.Minute)
+		}
+	})
+
+	t.Run("successes and definitive answers reset the failure count", func(t *testing.T) {
+		breaker, _ := newTestBreaker()
+
+		breaker.Allow()
+		breaker.Record(true)
+		breaker.Allow()
+		breaker.Record(false)
+		breaker.Allow()
+		breaker.Record(true)
+
+		if breaker.State() != CircuitClosed {
+			t.Errorf("State() = %v, want %v", breaker.State(), CircuitClosed)
+		}
+	})
+
+	t.Run("half-open lets a single probe through", func(t *testing.T) {
+		breaker, now := newTestBreaker()
+		breaker.Allow()
+		breaker.Record(true)
+		breaker.Allow()
+		breaker.Record(true)
+
+		*now = now.Add(testBreakerConfig.OpenDuration)
+
+		if breaker.State() != CircuitHalfOpen {
+			t.Fatalf("State() = %v, want %v", breaker.State(), CircuitHalfOpen)
+		}
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("Allow() probe unexpected error = %v", err)
+		}
+		if err := breaker.Allow(); !errors.Is(err, CircuitOpenErr) {
+			t.Errorf("Allow() during probe error = %v, want %v", err, CircuitOpenErr)
+		}
+
+		breaker.Record(false)
+		if breaker.State() != CircuitClosed {
+			t.Errorf("State() after successful probe = %v, want %v", breaker.State(), CircuitClosed)
+		}
+	})
+
+	t.Run("failed probe re-opens the circuit", func(t *testing.T) {
+		breaker, now := newTestBreaker()
+		breaker.Allow()
+		breaker.Record(true)
+		breaker.Allow()
+		breaker.Record(true)
+
+		*now = now.Add(testBreakerConfig.OpenDuration)
+		breaker.Allow()
+		breaker.Record(true)
+
+		if breaker.State() != CircuitOpen {
+			t.Errorf("State() after failed probe = %v, want %v", breaker.State(), CircuitOpen)
+		}
+	})
+
+	t.Run("disabled breaker always allows", func(t *testing.T) {
+		breaker := NewCircuitBreaker("Fake", conf.CircuitBreakerConfig{})
+		for i := 0; i < 10; i++ {
+			breaker.Record(true)
+		}
+
+		if err := breaker.Allow(); err != nil {
+			t.Errorf("Allow() unexpected error = %v", err)
+		}
+		if breaker.State() != CircuitClosed {
+			t.Errorf("State() = %v, want %v", breaker.State(), CircuitClosed)
+		}
+	})
+}
+
+func TestChainFetchCurrent_CircuitBreaker(t *testing.T) {
+	downCalls, upCalls := 0, 0
+
+	registry := NewRegistry(testBreakerConfig, nil)
+	registry.Register(fakeProvider{name: "Down", err: errors.New("server error"), transient: true, calls: &downCalls})
+	registry.Register(fakeProvider{name: "Up", calls: &upCalls})
+
+	chain, err := registry.Chain([]WeatherProvider{"Down", "Up"}, 0)
+	if err != nil {
+		t.Fatalf("Registry.Chain() unexpected error = %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		result, err := chain.FetchCurrent(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if err != nil {
+			t.Fatalf("Chain.FetchCurrent() unexpected error = %v", err)
+		}
+		if result.Provider != "Up" {
+			t.Errorf("Chain.FetchCurrent() provider = %v, want Up", result.Provider)
+		}
+	}
+
+	if downCalls != testBreakerConfig.FailureThreshold {
+		t.Errorf("provider with open circuit called %v times, want %v", downCalls, testBreakerConfig.FailureThreshold)
+	}
+	if upCalls != 5 {
+		t.Errorf("fallback provider called %v times, want 5", upCalls)
+	}
+	if registry.Breaker("Down").State() != CircuitOpen {
+		t.Errorf("Breaker state = %v, want %v", registry.Breaker("Down").State(), CircuitOpen)
+	}
+}
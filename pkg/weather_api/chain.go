@@ -0,0 +1,184 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var (
+	NoProviderErr         = errors.New("no weather provider configured")
+	ProvidersExhaustedErr = errors.New("all weather providers failed")
+	// CapabilityNotSupportedErr is returned when no provider of a chain has the capability a call needs
+	CapabilityNotSupportedErr = errors.New("no configured weather provider supports this capability")
+	// NotFoundErr matches the errors of every provider knowing no such place
+	NotFoundErr = schemata.NotFoundErr
+)
+
+// Chain tries its providers in order. Transient failures fall through to the next
+// provider while definitive answers, such as a city not being found, stop the chain.
+// Providers whose circuit is open are skipped without being called, and so are the ones whose rate limit, applied
+// by their own transport to every request they send, is used up.
+type Chain struct {
+	providers []Provider
+	breakers  []*CircuitBreaker
+	timeout   time.Duration
+}
+
+// NewChain returns a Chain over providers. A non-zero timeout bounds each provider call.
+func NewChain(timeout time.Duration, providers ...Provider) Chain {
+	return Chain{
+		providers: providers,
+		breakers:  make([]*CircuitBreaker, len(providers)),
+		timeout:   timeout,
+	}
+}
+
+// Chain returns a Chain over the providers registered under names, in the given
+// order, guarded by their circuit breakers.
+func (r *Registry) Chain(names []WeatherProvider, timeout time.Duration) (Chain, error) {
+	chain := NewChain(timeout)
+	for _, name := range names {
+		provider, err := r.Get(name)
+		if err != nil {
+			return Chain{}, fmt.Errorf("%s: %w", name, err)
+		}
+
+		chain.providers = append(chain.providers, provider)
+		chain.breakers = append(chain.breakers, r.Breaker(name))
+	}
+
+	return chain, nil
+}
+
+// FetchCurrent returns the response of the first provider that answers. The
+// response's Provider field records which provider served it.
+func (c Chain) FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error) {
+	resp, provider, err := fetchFirst(ctx, c, func(ctx context.Context, provider Provider) (*schemata.FetchWeatherResponse, error) {
+		return provider.FetchCurrent(ctx, location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Provider = provider.Name()
+
+	return resp, nil
+}
+
+// fetchFirst returns the result of fetch for the first provider of c that answers, and that provider.
+func fetchFirst[T any](ctx context.Context, c Chain, fetch func(ctx context.Context, provider Provider) (T, error)) (T, Provider, error) {
+	var result T
+
+	if len(c.providers) == 0 {
+		return result, nil, NoProviderErr
+	}
+
+	errs := []error{ProvidersExhaustedErr}
+	for i, provider := range c.providers {
+		err := c.call(ctx, i, func(ctx context.Context) (err error) {
+			result, err = fetch(ctx, provider)
+			return err
+		})
+		if err == nil {
+			return result, provider, nil
+		}
+
+		if ctx.Err() != nil || !c.isTransient(i, err) {
+			return result, nil, err
+		}
+
+		log.Printf("weather provider %v failed transiently, falling through: %v", provider.Name(), err)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+
+	return result, nil, errors.Join(errs...)
+}
+
+// fetchFirstCapable is fetchFirst over the providers of c implementing C, the interface of capability.
+func fetchFirstCapable[C any, T any](ctx context.Context, c Chain, capability schemata.Capability, fetch func(ctx context.Context, provider C) (T, error)) (T, Provider, error) {
+	capable := c.filter(implements[C])
+
+	if len(c.providers) > 0 && len(capable.providers) == 0 {
+		var result T
+		return result, nil, fmt.Errorf("%s: %w", capability, CapabilityNotSupportedErr)
+	}
+
+	return fetchFirst(ctx, capable, func(ctx context.Context, provider Provider) (T, error) {
+		return fetch(ctx, provider.(C))
+	})
+}
+
+// fetchServed is fetchFirstCapable for the responses naming the provider that served them.
+func fetchServed[C any, R interface{ SetProvider(name string) }](ctx context.Context, c Chain, capability schemata.Capability, fetch func(ctx context.Context, provider C) (R, error)) (R, error) {
+	resp, provider, err := fetchFirstCapable(ctx, c, capability, fetch)
+	if err != nil {
+		var none R
+		return none, err
+	}
+
+	resp.SetProvider(provider.Name())
+
+	return resp, nil
+}
+
+// filter returns the chain of the providers of c matching keep, in the same order.
+func (c Chain) filter(keep func(provider Provider) bool) Chain {
+	filtered := NewChain(c.timeout)
+	for i, provider := range c.providers {
+		if !keep(provider) {
+			continue
+		}
+
+		filtered.providers = append(filtered.providers, provider)
+		filtered.breakers = append(filtered.breakers, c.breakers[i])
+	}
+
+	return filtered
+}
+
+func (c Chain) fetchCurrent(ctx context.Context, i int, location schemata.Location) (resp *schemata.FetchWeatherResponse, err error) {
+	err = c.call(ctx, i, func(ctx context.Context) error {
+		resp, err = c.providers[i].FetchCurrent(ctx, location)
+		return err
+	})
+
+	return resp, err
+}
+
+// call runs fn against the i-th provider, bounded by the chain timeout and guarded by
+// the provider's circuit breaker.
+func (c Chain) call(ctx context.Context, i int, fn func(ctx context.Context) error) error {
+	if err := c.breakers[i].Allow(); err != nil {
+		return err
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	err := fn(ctx)
+
+	// neither a call aborted by the caller nor hitting our own limit says anything about the provider's health
+	if errors.Is(ctx.Err(), context.Canceled) || isLimited(err) {
+		c.breakers[i].Release()
+	} else {
+		c.breakers[i].Record(err != nil && c.providers[i].IsTransient(err))
+	}
+
+	return err
+}
+
+func (c Chain) isTransient(i int, err error) bool {
+	return errors.Is(err, CircuitOpenErr) || isLimited(err) || c.providers[i].IsTransient(err)
+}
+
+func isLimited(err error) bool {
+	return errors.Is(err, RateLimitExceededErr) || errors.Is(err, QuotaExceededErr)
+}
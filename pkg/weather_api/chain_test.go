@@ -0,0 +1,264 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+type slowProvider struct {
+	fakeProvider
+}
+
+func (p slowProvider) FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// capableProvider answers air quality, alerts and history on top of the current weather.
+type capableProvider struct {
+	fakeProvider
+}
+
+func (p capableProvider) answer() error {
+	if p.calls != nil {
+		*p.calls++
+	}
+
+	return p.err
+}
+
+func (p capableProvider) FetchAirQuality(ctx context.Context, location schemata.Location) (*schemata.FetchAirQualityResponse, error) {
+	if err := p.answer(); err != nil {
+		return nil, err
+	}
+
+	return &schemata.FetchAirQualityResponse{LocationName: location.CityName, AQI: 2}, nil
+}
+
+func (p capableProvider) FetchAlerts(ctx context.Context, location schemata.Location) (*schemata.FetchAlertsResponse, error) {
+	if err := p.answer(); err != nil {
+		return nil, err
+	}
+
+	return &schemata.FetchAlertsResponse{LocationName: location.CityName, Alerts: []schemata.Alert{{Event: "Flood Watch"}}}, nil
+}
+
+func (p capableProvider) FetchHistory(ctx context.Context, location schemata.Location, from, to time.Time) (*schemata.FetchHistoryResponse, error) {
+	if err := p.answer(); err != nil {
+		return nil, err
+	}
+
+	return &schemata.FetchHistoryResponse{LocationName: location.CityName}, nil
+}
+
+func TestChainCapabilities(t *testing.T) {
+	location := schemata.Location{CityName: "London"}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		fetch func(chain Chain) (string, error)
+	}{
+		{
+			name: "air quality",
+			fetch: func(chain Chain) (string, error) {
+				resp, err := chain.FetchAirQuality(context.Background(), location)
+				if err != nil {
+					return "", err
+				}
+				return resp.Provider, nil
+			},
+		},
+		{
+			name: "alerts",
+			fetch: func(chain Chain) (string, error) {
+				resp, err := chain.FetchAlerts(context.Background(), location)
+				if err != nil {
+					return "", err
+				}
+				return resp.Provider, nil
+			},
+		},
+		{
+			name: "history",
+			fetch: func(chain Chain) (string, error) {
+				resp, err := chain.FetchHistory(context.Background(), location, from, from.Add(3*time.Hour))
+				if err != nil {
+					return "", err
+				}
+				return resp.Provider, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" skips incapable providers", func(t *testing.T) {
+			currentCalls, capableCalls := 0, 0
+			chain := NewChain(0,
+				fakeProvider{name: "Current", calls: &currentCalls},
+				capableProvider{fakeProvider{name: "Capable", calls: &capableCalls}},
+			)
+
+			provider, err := tt.fetch(chain)
+			if err != nil {
+				t.Fatalf("unexpected error = %v", err)
+			}
+			if provider != "Capable" {
+				t.Errorf("provider = %v, want Capable", provider)
+			}
+			if currentCalls != 0 || capableCalls != 1 {
+				t.Errorf("calls = %v current, %v capable, want 0 and 1", currentCalls, capableCalls)
+			}
+		})
+
+		t.Run(tt.name+" without capable providers", func(t *testing.T) {
+			chain := NewChain(0, fakeProvider{name: "Current"})
+
+			_, err := tt.fetch(chain)
+			if !errors.Is(err, CapabilityNotSupportedErr) {
+				t.Errorf("error = %v, want %v", err, CapabilityNotSupportedErr)
+			}
+		})
+	}
+}
+
+func TestChainFetchCurrent(t *testing.T) {
+	transientErr := errors.New("server error")
+	definitiveErr := errors.New("not found")
+
+	tests := []struct {
+		name             string
+		providers        []Provider
+		expectedProvider string
+		expectedError    error
+		expectedCalls    []int
+	}{
+		{
+			name: "first provider answers",
+			providers: []Provider{
+				fakeProvider{name: "A"},
+				fakeProvider{name: "B"},
+			},
+			expectedProvider: "A",
+			expectedCalls:    []int{1, 0},
+		},
+		{
+			name: "transient failure falls through",
+			providers: []Provider{
+				fakeProvider{name: "A", err: transientErr, transient: true},
+				fakeProvider{name: "B"},
+			},
+			expectedProvider: "B",
+			expectedCalls:    []int{1, 1},
+		},
+		{
+			name: "definitive failure stops the chain",
+			providers: []Provider{
+				fakeProvider{name: "A", err: definitiveErr},
+				fakeProvider{name: "B"},
+			},
+			expectedError: definitiveErr,
+			expectedCalls: []int{1, 0},
+		},
+		{
+			name: "every provider fails transiently",
+			providers: []Provider{
+				fakeProvider{name: "A", err: transientErr, transient: true},
+				fakeProvider{name: "B", err: transientErr, transient: true},
+			},
+			expectedError: ProvidersExhaustedErr,
+			expectedCalls: []int{1, 1},
+		},
+		{
+			name:          "no providers",
+			providers:     nil,
+			expectedError: NoProviderErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := make([]int, len(tt.providers))
+			for i, provider := range tt.providers {
+				fake := provider.(fakeProvider)
+				fake.calls = &calls[i]
+				tt.providers[i] = fake
+			}
+
+			result, err := NewChain(0, tt.providers...).FetchCurrent(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Chain.FetchCurrent() error = %v, want %v", err, tt.expectedError)
+				}
+				if result != nil {
+					t.Errorf("Chain.FetchCurrent() expected nil result but got %v", result)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Chain.FetchCurrent() unexpected error = %v", err)
+				}
+				if result.Provider != tt.expectedProvider {
+					t.Errorf("Chain.FetchCurrent() provider = %v, want %v", result.Provider, tt.expectedProvider)
+				}
+			}
+
+			for i, expected := range tt.expectedCalls {
+				if calls[i] != expected {
+					t.Errorf("provider %v called %v times, want %v", tt.providers[i].Name(), calls[i], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestChainFetchCurrent_Timeout(t *testing.T) {
+	chain := NewChain(10*time.Millisecond,
+		slowProvider{fakeProvider{name: "Slow", transient: true}},
+		fakeProvider{name: "Fast"},
+	)
+
+	result, err := chain.FetchCurrent(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+	if err != nil {
+		t.Fatalf("Chain.FetchCurrent() unexpected error = %v", err)
+	}
+	if result.Provider != "Fast" {
+		t.Errorf("Chain.FetchCurrent() provider = %v, want Fast", result.Provider)
+	}
+}
+
+func TestChainFetchCurrent_CancelledContext(t *testing.T) {
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	chain := NewChain(0,
+		slowProvider{fakeProvider{name: "Slow", transient: true}},
+		fakeProvider{name: "Fast", calls: &calls},
+	)
+
+	_, err := chain.FetchCurrent(ctx, schemata.Location{CityName: "London", Country: "GB"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Chain.FetchCurrent() error = %v, want %v", err, context.Canceled)
+	}
+	if calls != 0 {
+		t.Errorf("provider after a cancelled call was tried %v times, want 0", calls)
+	}
+}
+
+func TestRegistryChain(t *testing.T) {
+	registry := LoadRegistry(conf.Config{}, nil)
+
+	if _, err := registry.Chain([]WeatherProvider{OpenWeather, OpenMeteo}, 0); err != nil {
+		t.Errorf("Registry.Chain() unexpected error = %v", err)
+	}
+
+	if _, err := registry.Chain([]WeatherProvider{OpenWeather, "Unknown"}, 0); !errors.Is(err, ProviderNotImplementedErr) {
+		t.Errorf("Registry.Chain() error = %v, want %v", err, ProviderNotImplementedErr)
+	}
+}
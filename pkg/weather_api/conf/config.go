@@ -3,12 +3,69 @@ package conf
 import (
 	"github.com/caarlos0/env/v11"
 	"log"
+	"time"
 )
 
 type Config struct {
-	OpenWeather struct {
-		ApiKey string `env:"OPEN_WEATHER_API_KEY"`
+	// Providers is the ordered chain of providers used for fetching weather data.
+	// A provider is only tried when every provider before it failed transiently.
+	Providers []string `env:"WEATHER_PROVIDERS" envDefault:"OpenWeather"`
+	// ProviderTimeout bounds a single provider call so a hanging provider falls through to the next one
+	ProviderTimeout time.Duration `env:"WEATHER_PROVIDER_TIMEOUT" envDefault:"10s"`
+	// CacheTTL is how long a fetched weather is served again for the same location instead of calling the providers
+	CacheTTL time.Duration `env:"WEATHER_CACHE_TTL" envDefault:"1m"`
+	// ForecastTTL is how long a stored forecast is served before it is fetched again
+	ForecastTTL time.Duration `env:"WEATHER_FORECAST_TTL" envDefault:"3h"`
+	// AlertsTTL is how long the alerts fetched for a city are served again instead of calling the providers
+	AlertsTTL time.Duration `env:"WEATHER_ALERTS_TTL" envDefault:"10m"`
+	// ReverseGeocodingTTL is how long the location found for coordinates is served again for coordinates
+	// within about 100 meters of them instead of calling the providers
+	ReverseGeocodingTTL time.Duration `env:"WEATHER_REVERSE_GEOCODING_TTL" envDefault:"24h"`
+	// SearchTTL is how long the places the providers geocode a location search to are served again for the same search
+	SearchTTL      time.Duration `env:"WEATHER_LOCATION_SEARCH_TTL" envDefault:"1h"`
+	Consensus      ConsensusConfig
+	Retry          RetryConfig
+	CircuitBreaker CircuitBreakerConfig
+	OpenWeather    struct {
+		ApiKey    string          `env:"OPEN_WEATHER_API_KEY"`
+		RateLimit RateLimitConfig `envPrefix:"OPEN_WEATHER_"`
 	}
+	OpenMeteo struct {
+		RateLimit RateLimitConfig `envPrefix:"OPEN_METEO_"`
+	}
+}
+
+// ConsensusConfig holds the largest spread between provider readings that is not flagged as a disagreement
+type ConsensusConfig struct {
+	TemperatureSpread float64 `env:"WEATHER_CONSENSUS_TEMPERATURE_SPREAD" envDefault:"3"`
+	HumiditySpread    int     `env:"WEATHER_CONSENSUS_HUMIDITY_SPREAD" envDefault:"15"`
+	WindSpeedSpread   float64 `env:"WEATHER_CONSENSUS_WIND_SPEED_SPREAD" envDefault:"5"`
+}
+
+// RetryConfig controls how provider HTTP calls are retried. MaxAttempts counts the
+// first attempt too, so 1 (or less) disables retrying.
+type RetryConfig struct {
+	MaxAttempts    int           `env:"WEATHER_PROVIDER_RETRY_MAX_ATTEMPTS" envDefault:"3"`
+	InitialBackoff time.Duration `env:"WEATHER_PROVIDER_RETRY_INITIAL_BACKOFF" envDefault:"200ms"`
+	MaxBackoff     time.Duration `env:"WEATHER_PROVIDER_RETRY_MAX_BACKOFF" envDefault:"2s"`
+}
+
+// CircuitBreakerConfig controls the per-provider circuit breaker. A FailureThreshold
+// of 0 disables the breaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive transient failures opening the circuit
+	FailureThreshold int `env:"WEATHER_PROVIDER_BREAKER_FAILURE_THRESHOLD" envDefault:"5"`
+	// OpenDuration is how long an open circuit rejects calls before letting a probe through
+	OpenDuration time.Duration `env:"WEATHER_PROVIDER_BREAKER_OPEN_DURATION" envDefault:"30s"`
+}
+
+// RateLimitConfig caps the calls made to a provider with a single API key. Zero values
+// disable the respective limit.
+type RateLimitConfig struct {
+	RequestsPerMinute int   `env:"REQUESTS_PER_MINUTE"`
+	DailyQuota        int64 `env:"DAILY_QUOTA"`
+	// MaxWait is how long a call may be queued waiting for the per-minute limit before being rejected
+	MaxWait time.Duration `env:"RATE_LIMIT_MAX_WAIT" envDefault:"2s"`
 }
 
 func LoadFromEnv() Config {
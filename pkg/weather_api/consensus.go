@@ -0,0 +1,247 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// ConsensusProvider is the Provider name recorded on merged responses.
+const ConsensusProvider = "Consensus"
+
+// FetchConsensus queries every provider of the chain in parallel and merges the
+// successful readings. Providers that fail are left out of the consensus; it only
+// fails when no provider answered.
+func (c Chain) FetchConsensus(ctx context.Context, location schemata.Location, thresholds conf.ConsensusConfig) (*schemata.ConsensusResponse, error) {
+	if len(c.providers) == 0 {
+		return nil, NoProviderErr
+	}
+
+	responses := make([]*schemata.FetchWeatherResponse, len(c.providers))
+	errs := make([]error, len(c.providers))
+
+	var wg sync.WaitGroup
+	for i := range c.providers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			responses[i], errs[i] = c.fetchCurrent(ctx, i, location)
+		}(i)
+	}
+	wg.Wait()
+
+	readings := make([]schemata.FetchWeatherResponse, 0, len(c.providers))
+	for i, resp := range responses {
+		if errs[i] != nil {
+			continue
+		}
+
+		resp.Provider = c.providers[i].Name()
+		readings = append(readings, *resp)
+	}
+
+	if len(readings) == 0 {
+		return nil, c.consensusError(errs)
+	}
+
+	consensus := MergeReadings(readings, thresholds)
+
+	return &consensus, nil
+}
+
+// consensusError picks the error reported when no provider answered: the first
+// definitive answer if any, since it holds for the whole request, otherwise every failure.
+func (c Chain) consensusError(errs []error) error {
+	joined := []error{ProvidersExhaustedErr}
+	for i, err := range errs {
+		if !c.isTransient(i, err) {
+			return err
+		}
+
+		joined = append(joined, fmt.Errorf("%s: %w", c.providers[i].Name(), err))
+	}
+
+	return errors.Join(joined...)
+}
+
+// MergeReadings returns the consensus of readings: the median of numeric fields and
+// the majority description and condition. Optional fields are the median of the readings reporting
+// them. Location fields, times and wind direction, whose median points south for winds either
+// side of north, are taken from the first reading reporting them.
+func MergeReadings(readings []schemata.FetchWeatherResponse, thresholds conf.ConsensusConfig) schemata.ConsensusResponse {
+	temperatures := make([]float64, len(readings))
+	humidities := make([]float64, len(readings))
+	windSpeeds := make([]float64, len(readings))
+	descriptions := make([]string, len(readings))
+
+	for i, reading := range readings {
+		temperatures[i] = reading.Temperature
+		humidities[i] = float64(reading.Humidity)
+		windSpeeds[i] = reading.WindSpeed
+		descriptions[i] = reading.Description
+	}
+
+	description, hasMajority := majority(descriptions)
+	condition, intensity := majorityCondition(readings)
+
+	consensus := schemata.ConsensusResponse{
+		FetchWeatherResponse: schemata.FetchWeatherResponse{
+			Provider:     ConsensusProvider,
+			LocationName: readings[0].LocationName,
+			Country:      readings[0].Country,
+			Coordinates:  readings[0].Coordinates,
+			Temperature:  median(temperatures),
+			Description:  description,
+			Condition:    condition,
+			Intensity:    intensity,
+			Humidity:     int(math.Round(median(humidities))),
+			WindSpeed:    median(windSpeeds),
+			Pressure:     optionalIntMedian(readings, func(r schemata.FetchWeatherResponse) *int { return r.Pressure }),
+			WindDeg:      first(readings, func(r schemata.FetchWeatherResponse) *int { return r.WindDeg }),
+			WindGust:     optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.WindGust }),
+			FeelsLike:    optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.FeelsLike }),
+			TempMin:      optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.TempMin }),
+			TempMax:      optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.TempMax }),
+			Visibility:   optionalIntMedian(readings, func(r schemata.FetchWeatherResponse) *int { return r.Visibility }),
+			Clouds:       optionalIntMedian(readings, func(r schemata.FetchWeatherResponse) *int { return r.Clouds }),
+			Rain1h:       optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.Rain1h }),
+			Rain3h:       optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.Rain3h }),
+			Snow1h:       optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.Snow1h }),
+			Snow3h:       optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 { return r.Snow3h }),
+			Sunrise:      first(readings, func(r schemata.FetchWeatherResponse) *time.Time { return r.Sunrise }),
+			Sunset:       first(readings, func(r schemata.FetchWeatherResponse) *time.Time { return r.Sunset }),
+			ObservedAt:   first(readings, func(r schemata.FetchWeatherResponse) *time.Time { return r.ObservedAt }),
+		},
+		Readings: readings,
+	}
+
+	if spread(temperatures) > thresholds.TemperatureSpread {
+		consensus.Disagreements = append(consensus.Disagreements, "temperature")
+	}
+	if spread(humidities) > float64(thresholds.HumiditySpread) {
+		consensus.Disagreements = append(consensus.Disagreements, "humidity")
+	}
+	if spread(windSpeeds) > thresholds.WindSpeedSpread {
+		consensus.Disagreements = append(consensus.Disagreements, "wind_speed")
+	}
+	if !hasMajority {
+		consensus.Disagreements = append(consensus.Disagreements, "description")
+	}
+
+	return consensus
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
+
+// optionalMedian returns the median of the values of the readings reporting one, nil when none does.
+func optionalMedian(readings []schemata.FetchWeatherResponse, value func(schemata.FetchWeatherResponse) *float64) *float64 {
+	var values []float64
+	for _, r := range readings {
+		if v := value(r); v != nil {
+			values = append(values, *v)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	m := median(values)
+
+	return &m
+}
+
+func optionalIntMedian(readings []schemata.FetchWeatherResponse, value func(schemata.FetchWeatherResponse) *int) *int {
+	m := optionalMedian(readings, func(r schemata.FetchWeatherResponse) *float64 {
+		if v := value(r); v != nil {
+			f := float64(*v)
+			return &f
+		}
+
+		return nil
+	})
+	if m == nil {
+		return nil
+	}
+
+	rounded := int(math.Round(*m))
+
+	return &rounded
+}
+
+// first returns the value of the first reading reporting one, nil when none does.
+func first[T any](readings []schemata.FetchWeatherResponse, value func(schemata.FetchWeatherResponse) *T) *T {
+	for _, r := range readings {
+		if v := value(r); v != nil {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func spread(values []float64) float64 {
+	lowest, highest := values[0], values[0]
+	for _, v := range values[1:] {
+		lowest = math.Min(lowest, v)
+		highest = math.Max(highest, v)
+	}
+
+	return highest - lowest
+}
+
+// majorityCondition returns the majority condition of readings, graded by the majority intensity of the
+// readings reporting it.
+func majorityCondition(readings []schemata.FetchWeatherResponse) (schemata.Condition, schemata.Intensity) {
+	conditions := make([]string, len(readings))
+	for i, reading := range readings {
+		conditions[i] = string(reading.Condition)
+	}
+
+	condition, _ := majority(conditions)
+
+	var intensities []string
+	for _, reading := range readings {
+		if string(reading.Condition) == condition {
+			intensities = append(intensities, string(reading.Intensity))
+		}
+	}
+
+	intensity, _ := majority(intensities)
+
+	return schemata.Condition(condition), schemata.Intensity(intensity)
+}
+
+// majority returns the most common value, ties going to the value seen first, and
+// whether it was reported by more than half of values.
+func majority(values []string) (string, bool) {
+	counts := make(map[string]int, len(values))
+	winner := values[0]
+
+	for _, v := range values {
+		counts[v]++
+		if counts[v] > counts[winner] {
+			winner = v
+		}
+	}
+
+	return winner, counts[winner]*2 > len(values)
+}
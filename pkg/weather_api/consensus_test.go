@@ -0,0 +1,207 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var testThresholds = conf.ConsensusConfig{
+	TemperatureSpread: 3,
+	HumiditySpread:    15,
+	WindSpeedSpread:   5,
+}
+
+type readingProvider struct {
+	fakeProvider
+	reading schemata.FetchWeatherResponse
+}
+
+func (p readingProvider) FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error) {
+	reading := p.reading
+	return &reading, nil
+}
+
+func TestMergeReadings(t *testing.T) {
+	tests := []struct {
+		name                  string
+		readings              []schemata.FetchWeatherResponse
+		expectedTemperature   float64
+		expectedHumidity      int
+		expectedWindSpeed     float64
+		expectedDescription   string
+		expectedDisagreements []string
+	}{
+		{
+			name: "single reading",
+			readings: []schemata.FetchWeatherResponse{
+				{Temperature: 20, Humidity: 50, WindSpeed: 3, Description: "clear sky"},
+			},
+			expectedTemperature: 20,
+			expectedHumidity:    50,
+			expectedWindSpeed:   3,
+			expectedDescription: "clear sky",
+		},
+		{
+			name: "odd number of agreeing readings",
+			readings: []schemata.FetchWeatherResponse{
+				{Temperature: 20, Humidity: 50, WindSpeed: 3, Description: "clear sky"},
+				{Temperature: 21.5, Humidity: 55, WindSpeed: 4, Description: "clear sky"},
+				{Temperature: 19, Humidity: 48, WindSpeed: 2, Description: "few clouds"},
+			},
+			expectedTemperature: 20,
+			expectedHumidity:    50,
+			expectedWindSpeed:   3,
+			expectedDescription: "clear sky",
+		},
+		{
+			name: "even number of disagreeing readings",
+			readings: []schemata.FetchWeatherResponse{
+				{Temperature: 10, Humidity: 40, WindSpeed: 1, Description: "clear sky"},
+				{Temperature: 20, Humidity: 81, WindSpeed: 9, Description: "heavy rain"},
+			},
+			expectedTemperature:   15,
+			expectedHumidity:      61,
+			expectedWindSpeed:     5,
+			expectedDescription:   "clear sky",
+			expectedDisagreements: []string{"temperature", "humidity", "wind_speed", "description"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MergeReadings(tt.readings, testThresholds)
+
+			if result.Provider != ConsensusProvider {
+				t.Errorf("MergeReadings() provider = %v, want %v", result.Provider, ConsensusProvider)
+			}
+			if result.Temperature != tt.expectedTemperature {
+				t.Errorf("MergeReadings() temperature = %v, want %v", result.Temperature, tt.expectedTemperature)
+			}
+			if result.Humidity != tt.expectedHumidity {
+				t.Errorf("MergeReadings() humidity = %v, want %v", result.Humidity, tt.expectedHumidity)
+			}
+			if result.WindSpeed != tt.expectedWindSpeed {
+				t.Errorf("MergeReadings() wind speed = %v, want %v", result.WindSpeed, tt.expectedWindSpeed)
+			}
+			if result.Description != tt.expectedDescription {
+				t.Errorf("MergeReadings() description = %v, want %v", result.Description, tt.expectedDescription)
+			}
+			if !reflect.DeepEqual(result.Disagreements, tt.expectedDisagreements) {
+				t.Errorf("MergeReadings() disagreements = %v, want %v", result.Disagreements, tt.expectedDisagreements)
+			}
+			if len(result.Readings) != len(tt.readings) {
+				t.Errorf("MergeReadings() readings = %v, want %v", len(result.Readings), len(tt.readings))
+			}
+		})
+	}
+}
+
+func TestChainFetchConsensus(t *testing.T) {
+	transientErr := errors.New("server error")
+	definitiveErr := errors.New("not found")
+
+	t.Run("merges the readings of providers that answered", func(t *testing.T) {
+		chain := NewChain(0,
+			readingProvider{fakeProvider{name: "A"}, schemata.FetchWeatherResponse{LocationName: "London", Temperature: 10}},
+			fakeProvider{name: "B", err: transientErr, transient: true},
+			readingProvider{fakeProvider{name: "C"}, schemata.FetchWeatherResponse{LocationName: "London", Temperature: 12}},
+		)
+
+		result, err := chain.FetchConsensus(context.Background(), schemata.Location{CityName: "London", Country: "GB"}, testThresholds)
+		if err != nil {
+			t.Fatalf("Chain.FetchConsensus() unexpected error = %v", err)
+		}
+		if result.Temperature != 11 {
+			t.Errorf("Chain.FetchConsensus() temperature = %v, want 11", result.Temperature)
+		}
+		if len(result.Readings) != 2 || result.Readings[0].Provider != "A" || result.Readings[1].Provider != "C" {
+			t.Errorf("Chain.FetchConsensus() readings = %v, want readings of A and C", result.Readings)
+		}
+	})
+
+	t.Run("reports a definitive answer when nobody answered", func(t *testing.T) {
+		chain := NewChain(0,
+			fakeProvider{name: "A", err: transientErr, transient: true},
+			fakeProvider{name: "B", err: definitiveErr},
+		)
+
+		_, err := chain.FetchConsensus(context.Background(), schemata.Location{CityName: "London", Country: "GB"}, testThresholds)
+		if !errors.Is(err, definitiveErr) {
+			t.Errorf("Chain.FetchConsensus() error = %v, want %v", err, definitiveErr)
+		}
+	})
+
+	t.Run("reports every failure when all failed transiently", func(t *testing.T) {
+		chain := NewChain(0,
+			fakeProvider{name: "A", err: transientErr, transient: true},
+			fakeProvider{name: "B", err: transientErr, transient: true},
+		)
+
+		_, err := chain.FetchConsensus(context.Background(), schemata.Location{CityName: "London", Country: "GB"}, testThresholds)
+		if !errors.Is(err, ProvidersExhaustedErr) {
+			t.Errorf("Chain.FetchConsensus() error = %v, want %v", err, ProvidersExhaustedErr)
+		}
+	})
+
+	t.Run("no providers", func(t *testing.T) {
+		_, err := NewChain(0).FetchConsensus(context.Background(), schemata.Location{CityName: "London", Country: "GB"}, testThresholds)
+		if !errors.Is(err, NoProviderErr) {
+			t.Errorf("Chain.FetchConsensus() error = %v, want %v", err, NoProviderErr)
+		}
+	})
+}
+
+func TestMergeReadings_OptionalFields(t *testing.T) {
+	pressures := []int{1010, 1013, 1020}
+	north, northWest := 10, 350
+	gust := 7.5
+	observedAt := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	readings := []schemata.FetchWeatherResponse{
+		{Pressure: &pressures[0], WindDeg: &north},
+		{Pressure: &pressures[1], WindDeg: &northWest, WindGust: &gust, ObservedAt: &observedAt},
+		{Pressure: &pressures[2]},
+	}
+
+	result := MergeReadings(readings, testThresholds)
+
+	if result.Pressure == nil || *result.Pressure != 1013 {
+		t.Errorf("MergeReadings() pressure = %v, want 1013", result.Pressure)
+	}
+	if result.WindDeg == nil || *result.WindDeg != north {
+		t.Errorf("MergeReadings() wind direction = %v, want %v", result.WindDeg, north)
+	}
+	if result.WindGust == nil || *result.WindGust != gust {
+		t.Errorf("MergeReadings() wind gust = %v, want %v", result.WindGust, gust)
+	}
+	if result.ObservedAt == nil || !result.ObservedAt.Equal(observedAt) {
+		t.Errorf("MergeReadings() observed at = %v, want %v", result.ObservedAt, observedAt)
+	}
+	if result.Clouds != nil {
+		t.Errorf("MergeReadings() clouds = %v, want nil when no reading reports it", *result.Clouds)
+	}
+}
+
+func TestMergeReadings_Condition(t *testing.T) {
+	readings := []schemata.FetchWeatherResponse{
+		{Description: "light rain", Condition: schemata.ConditionRain, Intensity: schemata.IntensityLight},
+		{Description: "overcast clouds", Condition: schemata.ConditionClouds, Intensity: schemata.IntensityHeavy},
+		{Description: "heavy rain", Condition: schemata.ConditionRain, Intensity: schemata.IntensityHeavy},
+		{Description: "heavy intensity rain", Condition: schemata.ConditionRain, Intensity: schemata.IntensityHeavy},
+	}
+
+	result := MergeReadings(readings, testThresholds)
+
+	if result.Condition != schemata.ConditionRain {
+		t.Errorf("MergeReadings() condition = %q, want %q", result.Condition, schemata.ConditionRain)
+	}
+	if result.Intensity != schemata.IntensityHeavy {
+		t.Errorf("MergeReadings() intensity = %q, want %q", result.Intensity, schemata.IntensityHeavy)
+	}
+}
@@ -0,0 +1,20 @@
+package weather_api
+
+import (
+	"context"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// Forecaster is implemented by providers with the schemata.ForecastCapability.
+type Forecaster interface {
+	FetchForecast(ctx context.Context, location schemata.Location) (*schemata.FetchForecastResponse, error)
+}
+
+// FetchForecast returns the forecast of the first provider of the chain able to forecast that answers.
+// Providers without the forecast capability are skipped.
+func (c Chain) FetchForecast(ctx context.Context, location schemata.Location) (*schemata.FetchForecastResponse, error) {
+	return fetchServed(ctx, c, schemata.ForecastCapability, func(ctx context.Context, forecaster Forecaster) (*schemata.FetchForecastResponse, error) {
+		return forecaster.FetchForecast(ctx, location)
+	})
+}
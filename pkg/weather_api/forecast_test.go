@@ -0,0 +1,82 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+type forecastProvider struct {
+	fakeProvider
+}
+
+func (p forecastProvider) FetchForecast(ctx context.Context, location schemata.Location) (*schemata.FetchForecastResponse, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &schemata.FetchForecastResponse{LocationName: location.CityName, Country: location.Country}, nil
+}
+
+func TestChainFetchForecast(t *testing.T) {
+	t.Run("skips providers unable to forecast", func(t *testing.T) {
+		currentCalls, forecastCalls := 0, 0
+		chain := NewChain(0,
+			fakeProvider{name: "Current", calls: &currentCalls},
+			forecastProvider{fakeProvider{name: "Forecast", calls: &forecastCalls}},
+		)
+
+		result, err := chain.FetchForecast(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if err != nil {
+			t.Fatalf("Chain.FetchForecast() unexpected error = %v", err)
+		}
+		if result.Provider != "Forecast" {
+			t.Errorf("Chain.FetchForecast() provider = %v, want Forecast", result.Provider)
+		}
+		if currentCalls != 0 || forecastCalls != 1 {
+			t.Errorf("calls = %v current, %v forecast, want 0 and 1", currentCalls, forecastCalls)
+		}
+	})
+
+	t.Run("falls through transient failures", func(t *testing.T) {
+		registry := NewRegistry(conf.CircuitBreakerConfig{}, nil)
+		registry.Register(forecastProvider{fakeProvider{name: "Down", err: errors.New("server error"), transient: true}})
+		registry.Register(forecastProvider{fakeProvider{name: "Up"}})
+
+		chain, err := registry.Chain([]WeatherProvider{"Down", "Up"}, 0)
+		if err != nil {
+			t.Fatalf("Registry.Chain() unexpected error = %v", err)
+		}
+
+		result, err := chain.FetchForecast(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if err != nil {
+			t.Fatalf("Chain.FetchForecast() unexpected error = %v", err)
+		}
+		if result.Provider != "Up" {
+			t.Errorf("Chain.FetchForecast() provider = %v, want Up", result.Provider)
+		}
+	})
+
+	t.Run("no provider able to forecast", func(t *testing.T) {
+		chain := NewChain(0, fakeProvider{name: "Current"})
+
+		_, err := chain.FetchForecast(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if !errors.Is(err, CapabilityNotSupportedErr) {
+			t.Errorf("Chain.FetchForecast() error = %v, want %v", err, CapabilityNotSupportedErr)
+		}
+	})
+
+	t.Run("empty chain", func(t *testing.T) {
+		_, err := NewChain(0).FetchForecast(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if !errors.Is(err, NoProviderErr) {
+			t.Errorf("Chain.FetchForecast() error = %v, want %v", err, NoProviderErr)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package weather_api
+
+import (
+	"context"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// Geocoder is implemented by providers with the schemata.GeocodingCapability.
+type Geocoder interface {
+	// Geocode returns the places named location.CityName, in location.Country when it is set, best match first.
+	Geocode(ctx context.Context, location schemata.Location) ([]schemata.Place, error)
+}
+
+// ReverseGeocoder is implemented by providers with the schemata.ReverseGeocodingCapability.
+type ReverseGeocoder interface {
+	// ReverseGeocode returns the places nearest to coordinates, nearest first.
+	ReverseGeocode(ctx context.Context, coordinates schemata.Coordinates) ([]schemata.Place, error)
+}
+
+// Geocode returns the places found by the first provider of the chain able to geocode that answers.
+// Providers without the geocoding capability are skipped.
+func (c Chain) Geocode(ctx context.Context, location schemata.Location) (*schemata.GeocodingResponse, error) {
+	places, provider, err := fetchFirstCapable(ctx, c, schemata.GeocodingCapability, func(ctx context.Context, geocoder Geocoder) ([]schemata.Place, error) {
+		return geocoder.Geocode(ctx, location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemata.GeocodingResponse{Provider: provider.Name(), Places: places}, nil
+}
+
+// ReverseGeocode returns the places nearest to coordinates found by the first provider of the chain able to
+// reverse geocode that answers. Providers without the reverse geocoding capability are skipped.
+func (c Chain) ReverseGeocode(ctx context.Context, coordinates schemata.Coordinates) (*schemata.GeocodingResponse, error) {
+	places, provider, err := fetchFirstCapable(ctx, c, schemata.ReverseGeocodingCapability, func(ctx context.Context, geocoder ReverseGeocoder) ([]schemata.Place, error) {
+		return geocoder.ReverseGeocode(ctx, coordinates)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemata.GeocodingResponse{Provider: provider.Name(), Places: places}, nil
+}
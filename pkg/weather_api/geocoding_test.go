@@ -0,0 +1,115 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+type geocodingProvider struct {
+	fakeProvider
+}
+
+func (p geocodingProvider) Geocode(ctx context.Context, location schemata.Location) ([]schemata.Place, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return []schemata.Place{{Name: location.CityName, Country: location.Country}}, nil
+}
+
+type reverseGeocodingProvider struct {
+	fakeProvider
+}
+
+func (p reverseGeocodingProvider) ReverseGeocode(ctx context.Context, coordinates schemata.Coordinates) ([]schemata.Place, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return []schemata.Place{{Name: "Nearest", Latitude: coordinates.Latitude, Longitude: coordinates.Longitude}}, nil
+}
+
+func TestChainGeocode(t *testing.T) {
+	t.Run("skips providers unable to geocode", func(t *testing.T) {
+		currentCalls, geocodingCalls := 0, 0
+		chain := NewChain(0,
+			fakeProvider{name: "Current", calls: &currentCalls},
+			geocodingProvider{fakeProvider{name: "Geocoding", calls: &geocodingCalls}},
+		)
+
+		result, err := chain.Geocode(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if err != nil {
+			t.Fatalf("Chain.Geocode() unexpected error = %v", err)
+		}
+		if result.Provider != "Geocoding" {
+			t.Errorf("Chain.Geocode() provider = %v, want Geocoding", result.Provider)
+		}
+		if len(result.Places) != 1 || result.Places[0].Name != "London" {
+			t.Errorf("Chain.Geocode() places = %v, want London", result.Places)
+		}
+		if currentCalls != 0 || geocodingCalls != 1 {
+			t.Errorf("calls = %v current, %v geocoding, want 0 and 1", currentCalls, geocodingCalls)
+		}
+	})
+
+	t.Run("falls through transient failures", func(t *testing.T) {
+		chain := NewChain(0,
+			geocodingProvider{fakeProvider{name: "Down", err: errors.New("server error"), transient: true}},
+			geocodingProvider{fakeProvider{name: "Up"}},
+		)
+
+		result, err := chain.Geocode(context.Background(), schemata.Location{CityName: "London"})
+		if err != nil {
+			t.Fatalf("Chain.Geocode() unexpected error = %v", err)
+		}
+		if result.Provider != "Up" {
+			t.Errorf("Chain.Geocode() provider = %v, want Up", result.Provider)
+		}
+	})
+
+	t.Run("no provider able to geocode", func(t *testing.T) {
+		_, err := NewChain(0, fakeProvider{name: "Current"}).Geocode(context.Background(), schemata.Location{CityName: "London"})
+		if !errors.Is(err, CapabilityNotSupportedErr) {
+			t.Errorf("Chain.Geocode() error = %v, want %v", err, CapabilityNotSupportedErr)
+		}
+	})
+}
+
+func TestChainReverseGeocode(t *testing.T) {
+	t.Run("skips providers unable to reverse geocode", func(t *testing.T) {
+		geocodingCalls, reverseCalls := 0, 0
+		chain := NewChain(0,
+			geocodingProvider{fakeProvider{name: "Geocoding", calls: &geocodingCalls}},
+			reverseGeocodingProvider{fakeProvider{name: "Reverse", calls: &reverseCalls}},
+		)
+
+		result, err := chain.ReverseGeocode(context.Background(), schemata.Coordinates{Latitude: 51.5, Longitude: -0.12})
+		if err != nil {
+			t.Fatalf("Chain.ReverseGeocode() unexpected error = %v", err)
+		}
+		if result.Provider != "Reverse" {
+			t.Errorf("Chain.ReverseGeocode() provider = %v, want Reverse", result.Provider)
+		}
+		if geocodingCalls != 0 || reverseCalls != 1 {
+			t.Errorf("calls = %v geocoding, %v reverse, want 0 and 1", geocodingCalls, reverseCalls)
+		}
+	})
+
+	t.Run("no provider able to reverse geocode", func(t *testing.T) {
+		_, err := NewChain(0, geocodingProvider{fakeProvider{name: "Geocoding"}}).ReverseGeocode(context.Background(), schemata.Coordinates{})
+		if !errors.Is(err, CapabilityNotSupportedErr) {
+			t.Errorf("Chain.ReverseGeocode() error = %v, want %v", err, CapabilityNotSupportedErr)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package weather_api
+
+import (
+	"context"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// Historian is implemented by providers with the schemata.HistoryCapability.
+type Historian interface {
+	// FetchHistory returns the hourly observations at location from from, inclusive, to to, exclusive.
+	FetchHistory(ctx context.Context, location schemata.Location, from, to time.Time) (*schemata.FetchHistoryResponse, error)
+}
+
+// FetchHistory returns the observations of the first provider of the chain with history that answers.
+// Providers without the history capability are skipped.
+func (c Chain) FetchHistory(ctx context.Context, location schemata.Location, from, to time.Time) (*schemata.FetchHistoryResponse, error) {
+	return fetchServed(ctx, c, schemata.HistoryCapability, func(ctx context.Context, historian Historian) (*schemata.FetchHistoryResponse, error) {
+		return historian.FetchHistory(ctx, location, from, to)
+	})
+}
@@ -0,0 +1,269 @@
+package weather_api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+)
+
+var (
+	RateLimitExceededErr = errors.New("rate-limit-exceeded")
+	QuotaExceededErr     = errors.New("quota-exceeded")
+)
+
+// LimitError is returned instead of calling a provider whose rate limit or daily quota
+// is used up. It unwraps to RateLimitExceededErr or QuotaExceededErr.
+type LimitError struct {
+	Provider string
+	Err      error
+	// RetryAfter is the time left until the limit lets a call through again
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// Limited is implemented by providers whose upstream caps the requests made with an API key. Limit makes
+// every request the provider sends, retries and health checks included, wait for acquire first.
+type Limited interface {
+	RateLimit() conf.RateLimitConfig
+	APIKey() string
+	Limit(acquire func(ctx context.Context) error)
+}
+
+// QuotaStore persists the number of requests made per provider, API key and UTC day.
+// Keys are identified by a hash, never by the key itself.
+type QuotaStore interface {
+	// Take accounts for one request unless quota requests, when it is positive, were already made that day,
+	// reporting whether it did, in a single atomic step so that concurrent requests can't overshoot the quota
+	Take(ctx context.Context, provider, keyID, day string, quota int64) (bool, error)
+	Count(ctx context.Context, provider, keyID, day string) (int64, error)
+}
+
+// QuotaStatus describes how much of its limits a provider has left.
+type QuotaStatus struct {
+	RequestsPerMinute int
+	AvailableTokens   int
+	DailyQuota        int64
+	UsedToday         int64
+	// RemainingToday is -1 when the provider has no daily quota
+	RemainingToday int64
+}
+
+// Limiter applies a provider's per-minute limit and daily quota before each request,
+// queueing requests up to MaxWait when the per-minute limit is reached.
+type Limiter struct {
+	provider string
+	keyID    string
+	config   conf.RateLimitConfig
+	bucket   *TokenBucket
+	store    QuotaStore
+
+	now func() time.Time
+}
+
+func NewLimiter(provider, apiKey string, config conf.RateLimitConfig, store QuotaStore) *Limiter {
+	limiter := &Limiter{
+		provider: provider,
+		keyID:    hashKey(apiKey),
+		config:   config,
+		store:    store,
+		now:      time.Now,
+	}
+
+	if config.RequestsPerMinute > 0 {
+		limiter.bucket = NewTokenBucket(config.RequestsPerMinute)
+	}
+
+	return limiter
+}
+
+// Acquire blocks until a request may be sent to the provider and accounts for it, or
+// returns a *LimitError when the request would break a limit.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	var wait time.Duration
+	if l.bucket != nil {
+		maxWait := l.config.MaxWait
+		if deadline, ok := ctx.Deadline(); ok {
+			maxWait = min(maxWait, time.Until(deadline))
+		}
+
+		var ok bool
+		wait, ok = l.bucket.Reserve(maxWait)
+		if !ok {
+			return &LimitError{Provider: l.provider, Err: RateLimitExceededErr, RetryAfter: wait}
+		}
+	}
+
+	// the request is accounted for before the wait, so that queued requests count against the quota too
+	taken, err := l.store.Take(ctx, l.provider, l.keyID, l.today(), l.config.DailyQuota)
+	if err != nil {
+		log.Printf("could not account %v request, letting it through: %v", l.provider, err)
+	} else if !taken {
+		return &LimitError{Provider: l.provider, Err: QuotaExceededErr, RetryAfter: l.untilTomorrow()}
+	}
+
+	return sleep(ctx, wait)
+}
+
+func (l *Limiter) Status(ctx context.Context) (QuotaStatus, error) {
+	status := QuotaStatus{
+		RequestsPerMinute: l.config.RequestsPerMinute,
+		DailyQuota:        l.config.DailyQuota,
+		RemainingToday:    -1,
+	}
+
+	if l.bucket != nil {
+		status.AvailableTokens = l.bucket.Available()
+	}
+
+	used, err := l.store.Count(ctx, l.provider, l.keyID, l.today())
+	if err != nil {
+		return QuotaStatus{}, err
+	}
+
+	status.UsedToday = used
+	if l.config.DailyQuota > 0 {
+		status.RemainingToday = max(l.config.DailyQuota-used, 0)
+	}
+
+	return status, nil
+}
+
+func (l *Limiter) today() string {
+	return l.now().UTC().Format(time.DateOnly)
+}
+
+func (l *Limiter) untilTomorrow() time.Duration {
+	now := l.now().UTC()
+	return now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+}
+
+func hashKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// TokenBucket holds up to RequestsPerMinute tokens, refilled continuously over a minute.
+type TokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	perSec   float64
+	last     time.Time
+
+	now func() time.Time
+}
+
+func NewTokenBucket(perMinute int) *TokenBucket {
+	return &TokenBucket{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+		perSec:   float64(perMinute) / 60,
+		last:     time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Reserve takes a token and returns how long the caller must wait before using it. When
+// that wait would exceed maxWait no token is taken and false is returned with the wait.
+func (b *TokenBucket) Reserve(maxWait time.Duration) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+
+	var wait time.Duration
+	if b.tokens < 1 {
+		wait = time.Duration(math.Ceil((1 - b.tokens) / b.perSec * float64(time.Second)))
+	}
+
+	if wait > maxWait {
+		return wait, false
+	}
+
+	b.tokens--
+
+	return wait, true
+}
+
+func (b *TokenBucket) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+
+	return int(math.Max(0, math.Floor(b.tokens)))
+}
+
+// refill adds the tokens earned since the last refill. b.mu must be held.
+func (b *TokenBucket) refill() {
+	now := b.now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.perSec)
+	b.last = now
+}
+
+// MemoryQuotaStore is a QuotaStore kept in memory, lost on restart.
+type MemoryQuotaStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{
+		counts: make(map[string]int64),
+	}
+}
+
+func (s *MemoryQuotaStore) Take(ctx context.Context, provider, keyID, day string, quota int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := provider + "|" + keyID + "|" + day
+	if quota > 0 && s.counts[key] >= quota {
+		return false, nil
+	}
+
+	s.counts[key]++
+
+	return true, nil
+}
+
+func (s *MemoryQuotaStore) Count(ctx context.Context, provider, keyID, day string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counts[provider+"|"+keyID+"|"+day], nil
+}
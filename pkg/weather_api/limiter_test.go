@@ -0,0 +1,232 @@
+package weather_api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+func newTestBucket(perMinute int) (*TokenBucket, *time.Time) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	bucket := NewTokenBucket(perMinute)
+	bucket.last = now
+	bucket.now = func() time.Time { return now }
+
+	return bucket, &now
+}
+
+func TestTokenBucket(t *testing.T) {
+	t.Run("allows a burst up to capacity", func(t *testing.T) {
+		bucket, _ := newTestBucket(3)
+
+		for i := 0; i < 3; i++ {
+			if wait, ok := bucket.Reserve(0); !ok || wait != 0 {
+				t.Fatalf("Reserve() = %v, %v, want 0, true", wait, ok)
+			}
+		}
+
+		if wait, ok := bucket.Reserve(0); ok || wait != 20*time.Second {
+			t.Errorf("Reserve() on empty bucket = %v, %v, want 20s, false", wait, ok)
+		}
+		if bucket.Available() != 0 {
+			t.Errorf("Available() = %v, want 0", bucket.Available())
+		}
+	})
+
+	t.Run("queues when the wait fits in maxWait", func(t *testing.T) {
+		bucket, _ := newTestBucket(60)
+		for i := 0; i < 60; i++ {
+			bucket.Reserve(0)
+		}
+
+		if wait, ok := bucket.Reserve(2 * time.Second); !ok || wait != time.Second {
+			t.Errorf("Reserve() = %v, %v, want 1s, true", wait, ok)
+		}
+		if wait, ok := bucket.Reserve(2 * time.Second); !ok || wait != 2*time.Second {
+			t.Errorf("Reserve() = %v, %v, want 2s, true", wait, ok)
+		}
+	})
+
+	t.Run("refills over time", func(t *testing.T) {
+		bucket, now := newTestBucket(60)
+		for i := 0; i < 60; i++ {
+			bucket.Reserve(0)
+		}
+
+		*now = now.Add(30 * time.Second)
+
+		if bucket.Available() != 30 {
+			t.Errorf("Available() = %v, want 30", bucket.Available())
+		}
+
+		*now = now.Add(time.Hour)
+
+		if bucket.Available() != 60 {
+			t.Errorf("Available() = %v, want capacity 60", bucket.Available())
+		}
+	})
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects calls once the daily quota is used", func(t *testing.T) {
+		store := NewMemoryQuotaStore()
+		limiter := NewLimiter("Fake", "secret", conf.RateLimitConfig{DailyQuota: 2}, store)
+
+		for i := 0; i < 2; i++ {
+			if err := limiter.Acquire(ctx); err != nil {
+				t.Fatalf("Acquire() unexpected error = %v", err)
+			}
+		}
+
+		err := limiter.Acquire(ctx)
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || !errors.Is(err, QuotaExceededErr) {
+			t.Fatalf("Acquire() error = %v, want %v", err, QuotaExceededErr)
+		}
+		if limitErr.RetryAfter <= 0 || limitErr.RetryAfter > 24*time.Hour {
+			t.Errorf("LimitError.RetryAfter = %v, want until the next UTC day", limitErr.RetryAfter)
+		}
+
+		status, err := limiter.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() unexpected error = %v", err)
+		}
+		if status.UsedToday != 2 || status.RemainingToday != 0 {
+			t.Errorf("Status() = %+v, want 2 used and 0 remaining", status)
+		}
+	})
+
+	t.Run("concurrent calls don't overshoot the daily quota", func(t *testing.T) {
+		limiter := NewLimiter("Fake", "secret", conf.RateLimitConfig{DailyQuota: 5}, NewMemoryQuotaStore())
+
+		var wg sync.WaitGroup
+		var allowed atomic.Int32
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if limiter.Acquire(ctx) == nil {
+					allowed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if allowed.Load() != 5 {
+			t.Errorf("allowed calls = %v, want the quota of 5", allowed.Load())
+		}
+	})
+
+	t.Run("rejects calls above the per-minute limit", func(t *testing.T) {
+		limiter := NewLimiter("Fake", "secret", conf.RateLimitConfig{RequestsPerMinute: 1}, NewMemoryQuotaStore())
+
+		if err := limiter.Acquire(ctx); err != nil {
+			t.Fatalf("Acquire() unexpected error = %v", err)
+		}
+
+		if err := limiter.Acquire(ctx); !errors.Is(err, RateLimitExceededErr) {
+			t.Errorf("Acquire() error = %v, want %v", err, RateLimitExceededErr)
+		}
+	})
+
+	t.Run("does not store the api key", func(t *testing.T) {
+		store := NewMemoryQuotaStore()
+		limiter := NewLimiter("Fake", "secret", conf.RateLimitConfig{}, store)
+
+		if err := limiter.Acquire(ctx); err != nil {
+			t.Fatalf("Acquire() unexpected error = %v", err)
+		}
+
+		for key := range store.counts {
+			if key != "Fake|"+hashKey("secret")+"|"+limiter.today() {
+				t.Errorf("stored quota key = %v, want a hashed api key", key)
+			}
+		}
+
+		status, _ := limiter.Status(ctx)
+		if status.UsedToday != 1 || status.RemainingToday != -1 {
+			t.Errorf("Status() = %+v, want 1 used and no quota", status)
+		}
+	})
+
+	t.Run("nil limiter allows every call", func(t *testing.T) {
+		var limiter *Limiter
+		if err := limiter.Acquire(ctx); err != nil {
+			t.Errorf("Acquire() unexpected error = %v", err)
+		}
+	})
+}
+
+// limitedProvider waits for the acquire its registry installs before each call, like a provider's transport does
+// before each request.
+type limitedProvider struct {
+	fakeProvider
+	rateLimit conf.RateLimitConfig
+	acquire   *func(ctx context.Context) error
+}
+
+func newLimitedProvider(provider fakeProvider, rateLimit conf.RateLimitConfig) limitedProvider {
+	return limitedProvider{fakeProvider: provider, rateLimit: rateLimit, acquire: new(func(ctx context.Context) error)}
+}
+
+func (p limitedProvider) RateLimit() conf.RateLimitConfig {
+	return p.rateLimit
+}
+
+func (p limitedProvider) APIKey() string {
+	return "secret"
+}
+
+func (p limitedProvider) Limit(acquire func(ctx context.Context) error) {
+	*p.acquire = acquire
+}
+
+func (p limitedProvider) FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error) {
+	if err := (*p.acquire)(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.fakeProvider.FetchCurrent(ctx, location)
+}
+
+func TestChainFetchCurrent_RateLimit(t *testing.T) {
+	limitedCalls, fallbackCalls := 0, 0
+
+	registry := NewRegistry(testBreakerConfig, nil)
+	registry.Register(newLimitedProvider(fakeProvider{name: "Limited", calls: &limitedCalls}, conf.RateLimitConfig{DailyQuota: 1}))
+	registry.Register(fakeProvider{name: "Fallback", calls: &fallbackCalls})
+
+	if registry.Limiter("Fallback") != nil {
+		t.Errorf("Registry.Limiter() of a provider that isn't Limited should be nil")
+	}
+
+	chain, err := registry.Chain([]WeatherProvider{"Limited", "Fallback"}, 0)
+	if err != nil {
+		t.Fatalf("Registry.Chain() unexpected error = %v", err)
+	}
+
+	providers := []string{}
+	for i := 0; i < 3; i++ {
+		result, err := chain.FetchCurrent(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if err != nil {
+			t.Fatalf("Chain.FetchCurrent() unexpected error = %v", err)
+		}
+		providers = append(providers, result.Provider)
+	}
+
+	if limitedCalls != 1 || fallbackCalls != 2 {
+		t.Errorf("calls = %v limited, %v fallback, want 1 and 2 (served by %v)", limitedCalls, fallbackCalls, providers)
+	}
+	if registry.Breaker("Limited").State() != CircuitClosed {
+		t.Errorf("hitting the quota should not open the circuit")
+	}
+}
@@ -0,0 +1,41 @@
+package weather_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var MapperNotImplementedErr = errors.New("provider cannot map stored payloads")
+
+// Mapper is implemented by providers able to map again a body they answered FetchCurrent with,
+// as found in FetchWeatherResponse.Raw. Mapping stored bodies picks up mapper fixes and fields
+// added since they were fetched.
+type Mapper interface {
+	MapCurrent(raw json.RawMessage) (*schemata.FetchWeatherResponse, error)
+}
+
+// MapCurrent maps raw, a body the provider registered under name answered FetchCurrent with, the
+// way the provider maps its answers today.
+func (r *Registry) MapCurrent(name WeatherProvider, raw json.RawMessage) (*schemata.FetchWeatherResponse, error) {
+	provider, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper, ok := provider.(Mapper)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, MapperNotImplementedErr)
+	}
+
+	resp, err := mapper.MapCurrent(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Provider = provider.Name()
+
+	return resp, nil
+}
@@ -0,0 +1,58 @@
+package weather_api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+type mapperProvider struct {
+	fakeProvider
+}
+
+func (p mapperProvider) MapCurrent(raw json.RawMessage) (*schemata.FetchWeatherResponse, error) {
+	var body struct {
+		Temp float64 `json:"temp"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+
+	return &schemata.FetchWeatherResponse{Temperature: body.Temp, Raw: raw}, nil
+}
+
+func TestRegistryMapCurrent(t *testing.T) {
+	registry := NewRegistry(conf.CircuitBreakerConfig{}, nil)
+	registry.Register(mapperProvider{fakeProvider{name: "Mapper"}})
+	registry.Register(fakeProvider{name: "Plain"})
+
+	t.Run("should map with the provider's mapper", func(t *testing.T) {
+		result, err := registry.MapCurrent("Mapper", json.RawMessage(`{"temp":21.5}`))
+		if err != nil {
+			t.Fatalf("Registry.MapCurrent() unexpected error = %v", err)
+		}
+		if result.Temperature != 21.5 {
+			t.Errorf("Registry.MapCurrent() temperature = %v, want 21.5", result.Temperature)
+		}
+		if result.Provider != "Mapper" {
+			t.Errorf("Registry.MapCurrent() provider = %v, want Mapper", result.Provider)
+		}
+	})
+
+	t.Run("should fail for providers without a mapper", func(t *testing.T) {
+		_, err := registry.MapCurrent("Plain", json.RawMessage(`{}`))
+		if !errors.Is(err, MapperNotImplementedErr) {
+			t.Errorf("Registry.MapCurrent() error = %v, want %v", err, MapperNotImplementedErr)
+		}
+	})
+
+	t.Run("should fail for unknown providers", func(t *testing.T) {
+		_, err := registry.MapCurrent("Unknown", json.RawMessage(`{}`))
+		if !errors.Is(err, ProviderNotImplementedErr) {
+			t.Errorf("Registry.MapCurrent() error = %v, want %v", err, ProviderNotImplementedErr)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package open_meteo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// dateLayout is the layout of the days the archive is queried for
+const dateLayout = "2006-01-02"
+
+// FetchHistoryByLocation returns the hourly observations at location from from, inclusive, to to,
+// exclusive, as archived by Open-Meteo. The archive lags a few days behind, so recent hours are left out.
+func FetchHistoryByLocation(ctx context.Context, location schemata.Location, from, to time.Time, config conf.Config) (*schemata.FetchHistoryResponse, error) {
+	return NewProvider(config).FetchHistory(ctx, location, from, to)
+}
+
+func (p Provider) FetchHistory(ctx context.Context, location schemata.Location, from, to time.Time) (*schemata.FetchHistoryResponse, error) {
+	place, err := p.resolve(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to = from.UTC(), to.UTC()
+
+	query := url.Values{}
+	query.Set("latitude", fmt.Sprint(place.Latitude))
+	query.Set("longitude", fmt.Sprint(place.Longitude))
+	query.Set("start_date", from.Format(dateLayout))
+	// the archive is queried by days, the last one included
+	query.Set("end_date", to.Add(-time.Nanosecond).Format(dateLayout))
+	query.Set("hourly", "temperature_2m,relative_humidity_2m,wind_speed_10m,weather_code,"+
+		"apparent_temperature,pressure_msl,wind_direction_10m,wind_gusts_10m,cloud_cover,rain")
+	query.Set("timezone", "GMT")
+	query.Set("wind_speed_unit", "ms")
+
+	var omResp HistoryResponse
+	if err := p.get(ctx, GetArchiveBaseURL()+"?"+query.Encode(), &omResp); err != nil {
+		return nil, err
+	}
+
+	dto := mapOpenMeteoHistoryResponseToFetchHistoryResponse(*place, omResp, from, to)
+
+	return &dto, nil
+}
@@ -0,0 +1,90 @@
+package open_meteo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveBody holds the last hours of 2025-01-01 and the first of 2025-01-02, the last one not observed yet
+const archiveBody = `{"latitude":48.85,"longitude":2.35,"hourly":{
+	"time":["2025-01-01T22:00","2025-01-01T23:00","2025-01-02T00:00","2025-01-02T01:00"],
+	"temperature_2m":[3.1,2.8,2.5,null],
+	"relative_humidity_2m":[80,82,85,null],
+	"wind_speed_10m":[2.5,2.1,1.9,null],
+	"weather_code":[3,61,61,null],
+	"pressure_msl":[1015.4,1015.6,1016.1,null],
+	"rain":[0,0.4,0.6,null]
+}}`
+
+func setupArchiveServer(t *testing.T, status int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "48.85", r.URL.Query().Get("latitude"))
+		assert.Equal(t, "2025-01-01", r.URL.Query().Get("start_date"))
+		assert.Equal(t, "2025-01-02", r.URL.Query().Get("end_date"))
+		assert.Equal(t, "ms", r.URL.Query().Get("wind_speed_unit"))
+
+		w.WriteHeader(status)
+		w.Write([]byte(archiveBody))
+	}))
+	t.Cleanup(server.Close)
+
+	original := GetArchiveBaseURL()
+	SetArchiveBaseURL(server.URL)
+	t.Cleanup(func() {
+		SetArchiveBaseURL(original)
+	})
+}
+
+func TestFetchHistoryByLocation(t *testing.T) {
+	location := schemata.Location{
+		CityName:    "Paris",
+		Country:     "FR",
+		Coordinates: &schemata.Coordinates{Latitude: 48.85, Longitude: 2.35},
+	}
+	from := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 2, 2, 0, 0, 0, time.UTC)
+
+	t.Run("observed hours within range", func(t *testing.T) {
+		setupArchiveServer(t, http.StatusOK)
+
+		result, err := FetchHistoryByLocation(context.Background(), location, from, to, conf.Config{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Paris", result.LocationName)
+		require.Len(t, result.Observations, 2, "hours before from and not observed yet are left out")
+
+		lightRain, pressure, rain := 61, 1016, 0.6
+		observedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, schemata.FetchWeatherResponse{
+			LocationName:  "Paris",
+			Country:       "FR",
+			Coordinates:   &schemata.Coordinates{Latitude: 48.85, Longitude: 2.35},
+			Temperature:   2.5,
+			Description:   "slight rain",
+			ConditionCode: &lightRain,
+			Condition:     schemata.ConditionRain,
+			Intensity:     schemata.IntensityLight,
+			Humidity:      85,
+			WindSpeed:     1.9,
+			Pressure:      &pressure,
+			Rain1h:        &rain,
+			ObservedAt:    &observedAt,
+		}, result.Observations[1])
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		setupArchiveServer(t, http.StatusTooManyRequests)
+
+		_, err := FetchHistoryByLocation(context.Background(), location, from, to, conf.Config{})
+
+		assert.ErrorIs(t, err, RateLimitedErr)
+	})
+}
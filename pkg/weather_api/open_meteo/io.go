@@ -0,0 +1,59 @@
+package open_meteo
+
+type GeocodingResponse struct {
+	Results []GeocodingResult `json:"results"`
+}
+
+type GeocodingResult struct {
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Country     string  `json:"country"`
+	CountryCode string  `json:"country_code"`
+	Admin1      string  `json:"admin1"`
+	Timezone    string  `json:"timezone"`
+}
+
+type Response struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Current   struct {
+		Time                string   `json:"time"`
+		Temperature2m       float64  `json:"temperature_2m"`
+		RelativeHumidity2m  int      `json:"relative_humidity_2m"`
+		WindSpeed10m        float64  `json:"wind_speed_10m"`
+		WeatherCode         int      `json:"weather_code"`
+		ApparentTemperature *float64 `json:"apparent_temperature"`
+		PressureMsl         *float64 `json:"pressure_msl"`
+		WindDirection10m    *int     `json:"wind_direction_10m"`
+		WindGusts10m        *float64 `json:"wind_gusts_10m"`
+		CloudCover          *int     `json:"cloud_cover"`
+		Visibility          *float64 `json:"visibility"`
+	} `json:"current"`
+	// Daily holds today's sunrise and sunset, a single day being asked for
+	Daily struct {
+		Sunrise []string `json:"sunrise"`
+		Sunset  []string `json:"sunset"`
+	} `json:"daily"`
+}
+
+// HistoryResponse is answered by the archive endpoint: one array per variable, the i-th value of each
+// being observed at Time[i]. Values are null for hours not observed yet.
+type HistoryResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Hourly    struct {
+		Time                []string   `json:"time"`
+		Temperature2m       []*float64 `json:"temperature_2m"`
+		RelativeHumidity2m  []*int     `json:"relative_humidity_2m"`
+		WindSpeed10m        []*float64 `json:"wind_speed_10m"`
+		WeatherCode         []*int     `json:"weather_code"`
+		ApparentTemperature []*float64 `json:"apparent_temperature"`
+		PressureMsl         []*float64 `json:"pressure_msl"`
+		WindDirection10m    []*int     `json:"wind_direction_10m"`
+		WindGusts10m        []*float64 `json:"wind_gusts_10m"`
+		CloudCover          []*int     `json:"cloud_cover"`
+		// Rain is the rain fallen over the hour before, in mm
+		Rain []*float64 `json:"rain"`
+	} `json:"hourly"`
+}
@@ -0,0 +1,204 @@
+package open_meteo
+
+import (
+	"math"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// wmoDescriptions maps WMO weather interpretation codes, as returned by Open-Meteo, to a description.
+var wmoDescriptions = map[int]string{
+	0:  "clear sky",
+	1:  "mainly clear",
+	2:  "partly cloudy",
+	3:  "overcast",
+	45: "fog",
+	48: "depositing rime fog",
+	51: "light drizzle",
+	53: "moderate drizzle",
+	55: "dense drizzle",
+	56: "light freezing drizzle",
+	57: "dense freezing drizzle",
+	61: "slight rain",
+	63: "moderate rain",
+	65: "heavy rain",
+	66: "light freezing rain",
+	67: "heavy freezing rain",
+	71: "slight snow fall",
+	73: "moderate snow fall",
+	75: "heavy snow fall",
+	77: "snow grains",
+	80: "slight rain showers",
+	81: "moderate rain showers",
+	82: "violent rain showers",
+	85: "slight snow showers",
+	86: "heavy snow showers",
+	95: "thunderstorm",
+	96: "thunderstorm with slight hail",
+	99: "thunderstorm with heavy hail",
+}
+
+type condition struct {
+	condition schemata.Condition
+	intensity schemata.Intensity
+}
+
+// wmoConditions maps WMO weather interpretation codes to conditions.
+var wmoConditions = map[int]condition{
+	0:  {schemata.ConditionClear, ""},
+	1:  {schemata.ConditionClouds, schemata.IntensityLight},
+	2:  {schemata.ConditionClouds, schemata.IntensityModerate},
+	3:  {schemata.ConditionClouds, schemata.IntensityHeavy},
+	45: {schemata.ConditionFog, schemata.IntensityModerate},
+	48: {schemata.ConditionFog, schemata.IntensityHeavy},
+	51: {schemata.ConditionDrizzle, schemata.IntensityLight},
+	53: {schemata.ConditionDrizzle, schemata.IntensityModerate},
+	55: {schemata.ConditionDrizzle, schemata.IntensityHeavy},
+	56: {schemata.ConditionDrizzle, schemata.IntensityLight},
+	57: {schemata.ConditionDrizzle, schemata.IntensityHeavy},
+	61: {schemata.ConditionRain, schemata.IntensityLight},
+	63: {schemata.ConditionRain, schemata.IntensityModerate},
+	65: {schemata.ConditionRain, schemata.IntensityHeavy},
+	66: {schemata.ConditionRain, schemata.IntensityLight},
+	67: {schemata.ConditionRain, schemata.IntensityHeavy},
+	71: {schemata.ConditionSnow, schemata.IntensityLight},
+	73: {schemata.ConditionSnow, schemata.IntensityModerate},
+	75: {schemata.ConditionSnow, schemata.IntensityHeavy},
+	77: {schemata.ConditionSnow, schemata.IntensityLight},
+	80: {schemata.ConditionRain, schemata.IntensityLight},
+	81: {schemata.ConditionRain, schemata.IntensityModerate},
+	82: {schemata.ConditionRain, schemata.IntensityHeavy},
+	85: {schemata.ConditionSnow, schemata.IntensityLight},
+	86: {schemata.ConditionSnow, schemata.IntensityHeavy},
+	95: {schemata.ConditionThunderstorm, schemata.IntensityModerate},
+	96: {schemata.ConditionThunderstorm, schemata.IntensityModerate},
+	99: {schemata.ConditionThunderstorm, schemata.IntensityHeavy},
+}
+
+func mapGeocodingResultToPlace(result GeocodingResult) schemata.Place {
+	return schemata.Place{
+		Name:      result.Name,
+		Country:   result.CountryCode,
+		State:     result.Admin1,
+		Latitude:  result.Latitude,
+		Longitude: result.Longitude,
+		Timezone:  result.Timezone,
+	}
+}
+
+// timeLayout is the layout of the times Open-Meteo answers, in the GMT timezone asked for
+const timeLayout = "2006-01-02T15:04"
+
+func mapOpenMeteoResponseToFetchWeatherResponse(location GeocodingResult, omResp Response) schemata.FetchWeatherResponse {
+	resp := schemata.FetchWeatherResponse{
+		LocationName:  location.Name,
+		Country:       location.CountryCode,
+		Coordinates:   &schemata.Coordinates{Latitude: omResp.Latitude, Longitude: omResp.Longitude},
+		Temperature:   omResp.Current.Temperature2m,
+		Description:   wmoDescriptions[omResp.Current.WeatherCode],
+		ConditionCode: &omResp.Current.WeatherCode,
+		Condition:     wmoConditions[omResp.Current.WeatherCode].condition,
+		Intensity:     wmoConditions[omResp.Current.WeatherCode].intensity,
+		Humidity:      omResp.Current.RelativeHumidity2m,
+		WindSpeed:     omResp.Current.WindSpeed10m,
+		FeelsLike:     omResp.Current.ApparentTemperature,
+		WindDeg:       omResp.Current.WindDirection10m,
+		WindGust:      omResp.Current.WindGusts10m,
+		Clouds:        omResp.Current.CloudCover,
+		ObservedAt:    parseTime(omResp.Current.Time),
+	}
+
+	if omResp.Current.PressureMsl != nil {
+		pressure := int(math.Round(*omResp.Current.PressureMsl))
+		resp.Pressure = &pressure
+	}
+	if omResp.Current.Visibility != nil {
+		visibility := int(math.Round(*omResp.Current.Visibility))
+		resp.Visibility = &visibility
+	}
+	if len(omResp.Daily.Sunrise) > 0 {
+		resp.Sunrise = parseTime(omResp.Daily.Sunrise[0])
+	}
+	if len(omResp.Daily.Sunset) > 0 {
+		resp.Sunset = parseTime(omResp.Daily.Sunset[0])
+	}
+
+	return resp
+}
+
+// mapOpenMeteoHistoryResponseToFetchHistoryResponse keeps the observed hours from from, inclusive, to
+// to, exclusive.
+func mapOpenMeteoHistoryResponseToFetchHistoryResponse(location GeocodingResult, omResp HistoryResponse, from, to time.Time) schemata.FetchHistoryResponse {
+	resp := schemata.FetchHistoryResponse{
+		LocationName: location.Name,
+		Country:      location.CountryCode,
+	}
+
+	hourly := omResp.Hourly
+	for i, value := range hourly.Time {
+		observedAt := parseTime(value)
+		if observedAt == nil || observedAt.Before(from) || !observedAt.Before(to) {
+			continue
+		}
+
+		// every variable is null for hours not observed yet
+		temperature := at(hourly.Temperature2m, i)
+		if temperature == nil {
+			continue
+		}
+
+		observation := schemata.FetchWeatherResponse{
+			LocationName: location.Name,
+			Country:      location.CountryCode,
+			Coordinates:  &schemata.Coordinates{Latitude: omResp.Latitude, Longitude: omResp.Longitude},
+			Temperature:  *temperature,
+			FeelsLike:    at(hourly.ApparentTemperature, i),
+			WindDeg:      at(hourly.WindDirection10m, i),
+			WindGust:     at(hourly.WindGusts10m, i),
+			Clouds:       at(hourly.CloudCover, i),
+			Rain1h:       at(hourly.Rain, i),
+			ObservedAt:   observedAt,
+		}
+
+		if humidity := at(hourly.RelativeHumidity2m, i); humidity != nil {
+			observation.Humidity = *humidity
+		}
+		if windSpeed := at(hourly.WindSpeed10m, i); windSpeed != nil {
+			observation.WindSpeed = *windSpeed
+		}
+		if code := at(hourly.WeatherCode, i); code != nil {
+			observation.Description = wmoDescriptions[*code]
+			observation.ConditionCode = code
+			observation.Condition = wmoConditions[*code].condition
+			observation.Intensity = wmoConditions[*code].intensity
+		}
+		if pressure := at(hourly.PressureMsl, i); pressure != nil {
+			rounded := int(math.Round(*pressure))
+			observation.Pressure = &rounded
+		}
+
+		resp.Observations = append(resp.Observations, observation)
+	}
+
+	return resp
+}
+
+// at returns the i-th value of values, nil when values is too short.
+func at[T any](values []*T, i int) *T {
+	if i >= len(values) {
+		return nil
+	}
+
+	return values[i]
+}
+
+// parseTime returns the time value, nil when it is empty or malformed.
+func parseTime(value string) *time.Time {
+	t, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
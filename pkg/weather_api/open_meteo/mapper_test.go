@@ -0,0 +1,92 @@
+package open_meteo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapOpenMeteoResponseToFetchWeatherResponse(t *testing.T) {
+	location := GeocodingResult{Name: "Berlin", Country: "Germany", CountryCode: "DE"}
+
+	tests := []struct {
+		name              string
+		weatherCode       int
+		expected          string
+		expectedCondition schemata.Condition
+		expectedIntensity schemata.Intensity
+	}{
+		{name: "clear sky", weatherCode: 0, expected: "clear sky", expectedCondition: schemata.ConditionClear},
+		{
+			name:              "heavy rain",
+			weatherCode:       65,
+			expected:          "heavy rain",
+			expectedCondition: schemata.ConditionRain,
+			expectedIntensity: schemata.IntensityHeavy,
+		},
+		{name: "unknown code", weatherCode: 42, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var omResp Response
+			omResp.Latitude, omResp.Longitude = 52.52, 13.41
+			omResp.Current.Temperature2m = -3.5
+			omResp.Current.RelativeHumidity2m = 90
+			omResp.Current.WindSpeed10m = 6.1
+			omResp.Current.WeatherCode = tt.weatherCode
+
+			result := mapOpenMeteoResponseToFetchWeatherResponse(location, omResp)
+
+			assert.Equal(t, schemata.FetchWeatherResponse{
+				LocationName:  "Berlin",
+				Country:       "DE",
+				Coordinates:   &schemata.Coordinates{Latitude: 52.52, Longitude: 13.41},
+				Temperature:   -3.5,
+				Description:   tt.expected,
+				ConditionCode: &tt.weatherCode,
+				Condition:     tt.expectedCondition,
+				Intensity:     tt.expectedIntensity,
+				Humidity:      90,
+				WindSpeed:     6.1,
+			}, result)
+		})
+	}
+}
+
+func TestMapOpenMeteoResponseToFetchWeatherResponse_Observation(t *testing.T) {
+	location := GeocodingResult{Name: "Berlin", Country: "Germany", CountryCode: "DE"}
+
+	pressure, visibility, gusts, apparent := 1012.6, 24140.0, 9.4, -7.2
+	direction, cover := 250, 75
+
+	var omResp Response
+	omResp.Current.Time = "2025-01-10T09:15"
+	omResp.Current.PressureMsl = &pressure
+	omResp.Current.Visibility = &visibility
+	omResp.Current.WindGusts10m = &gusts
+	omResp.Current.ApparentTemperature = &apparent
+	omResp.Current.WindDirection10m = &direction
+	omResp.Current.CloudCover = &cover
+	omResp.Daily.Sunrise = []string{"2025-01-10T07:13"}
+	omResp.Daily.Sunset = []string{"2025-01-10T15:25"}
+
+	result := mapOpenMeteoResponseToFetchWeatherResponse(location, omResp)
+
+	require.NotNil(t, result.Pressure)
+	assert.Equal(t, 1013, *result.Pressure)
+	require.NotNil(t, result.Visibility)
+	assert.Equal(t, 24140, *result.Visibility)
+	assert.Equal(t, &gusts, result.WindGust)
+	assert.Equal(t, &apparent, result.FeelsLike)
+	assert.Equal(t, &direction, result.WindDeg)
+	assert.Equal(t, &cover, result.Clouds)
+	assert.Equal(t, time.Date(2025, 1, 10, 9, 15, 0, 0, time.UTC), *result.ObservedAt)
+	assert.Equal(t, time.Date(2025, 1, 10, 7, 13, 0, 0, time.UTC), *result.Sunrise)
+	assert.Equal(t, time.Date(2025, 1, 10, 15, 25, 0, 0, time.UTC), *result.Sunset)
+	assert.Nil(t, result.TempMin)
+	assert.Nil(t, result.Rain1h)
+}
@@ -0,0 +1,201 @@
+package open_meteo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var (
+	baseURL          = "https://api.open-meteo.com/v1/forecast"
+	geocodingBaseURL = "https://geocoding-api.open-meteo.com/v1/search"
+	archiveBaseURL   = "https://archive-api.open-meteo.com/v1/archive"
+)
+
+// geocodingCandidates is the number of geocoding results inspected when matching a country.
+const geocodingCandidates = 10
+
+var (
+	// NotFoundErr wraps schemata.NotFoundErr
+	NotFoundErr    = fmt.Errorf("%w", schemata.NotFoundErr)
+	RateLimitedErr = errors.New("rate-limited")
+	UnhandledError = errors.New("unhandled-error")
+)
+
+// SetBaseURL allows setting the forecast base URL for testing purposes
+func SetBaseURL(url string) {
+	baseURL = url
+}
+
+// GetBaseURL returns the current forecast base URL
+func GetBaseURL() string {
+	return baseURL
+}
+
+// SetGeocodingBaseURL allows setting the geocoding base URL for testing purposes
+func SetGeocodingBaseURL(url string) {
+	geocodingBaseURL = url
+}
+
+// GetGeocodingBaseURL returns the current geocoding base URL
+func GetGeocodingBaseURL() string {
+	return geocodingBaseURL
+}
+
+// SetArchiveBaseURL allows setting the archive base URL for testing purposes
+func SetArchiveBaseURL(url string) {
+	archiveBaseURL = url
+}
+
+// GetArchiveBaseURL returns the current archive base URL
+func GetArchiveBaseURL() string {
+	return archiveBaseURL
+}
+
+// FetchWeatherByLocation fetches the current weather of a location through a provider built from config.
+func FetchWeatherByLocation(ctx context.Context, location schemata.Location, config conf.Config) (*schemata.FetchWeatherResponse, error) {
+	return NewProvider(config).FetchCurrent(ctx, location)
+}
+
+func (p Provider) FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error) {
+	place, err := p.resolve(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("latitude", fmt.Sprint(place.Latitude))
+	query.Set("longitude", fmt.Sprint(place.Longitude))
+	query.Set("current", "temperature_2m,relative_humidity_2m,wind_speed_10m,weather_code,"+
+		"apparent_temperature,pressure_msl,wind_direction_10m,wind_gusts_10m,cloud_cover,visibility")
+	query.Set("daily", "sunrise,sunset")
+	query.Set("forecast_days", "1")
+	query.Set("timezone", "GMT")
+	query.Set("wind_speed_unit", "ms")
+
+	var raw json.RawMessage
+	if err := p.get(ctx, GetBaseURL()+"?"+query.Encode(), &raw); err != nil {
+		return nil, err
+	}
+
+	dto, err := MapWeather(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	dto.LocationName = place.Name
+	dto.Country = place.CountryCode
+
+	return dto, nil
+}
+
+// MapWeather maps raw, a body answered by the forecast endpoint for current weather. The body
+// doesn't name the place, so LocationName and Country are left empty.
+func MapWeather(raw json.RawMessage) (*schemata.FetchWeatherResponse, error) {
+	var omResp Response
+	if err := json.Unmarshal(raw, &omResp); err != nil {
+		return nil, err
+	}
+
+	dto := mapOpenMeteoResponseToFetchWeatherResponse(GeocodingResult{}, omResp)
+	dto.Raw = raw
+
+	return &dto, nil
+}
+
+// resolve returns the place weather is fetched for: the location's coordinates as is, named
+// after the location, or its city geocoded since Open-Meteo is only queried by coordinates.
+func (p Provider) resolve(ctx context.Context, location schemata.Location) (*GeocodingResult, error) {
+	if location.Coordinates == nil {
+		return p.geocode(ctx, location.CityName, location.Country)
+	}
+
+	return &GeocodingResult{
+		Name:        location.CityName,
+		CountryCode: location.Country,
+		Latitude:    location.Coordinates.Latitude,
+		Longitude:   location.Coordinates.Longitude,
+	}, nil
+}
+
+// geocode resolves cityName to the first matching location. country may be either
+// an ISO 3166-1 alpha-2 code or a country name.
+func (p Provider) geocode(ctx context.Context, cityName, country string) (*GeocodingResult, error) {
+	results, err := p.search(ctx, cityName, country)
+	if err != nil {
+		return nil, err
+	}
+
+	return &results[0], nil
+}
+
+// search returns the places named cityName, in country when it isn't empty, in the order
+// Open-Meteo ranks them. Country matches either the country code or the country name.
+func (p Provider) search(ctx context.Context, cityName, country string) ([]GeocodingResult, error) {
+	query := url.Values{}
+	query.Set("name", cityName)
+	query.Set("count", fmt.Sprint(geocodingCandidates))
+	query.Set("format", "json")
+
+	var geoResp GeocodingResponse
+	if err := p.get(ctx, GetGeocodingBaseURL()+"?"+query.Encode(), &geoResp); err != nil {
+		return nil, err
+	}
+
+	results := make([]GeocodingResult, 0, len(geoResp.Results))
+	for _, result := range geoResp.Results {
+		if country == "" || strings.EqualFold(result.CountryCode, country) || strings.EqualFold(result.Country, country) {
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, NotFoundErr
+	}
+
+	return results, nil
+}
+
+func (p Provider) get(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Println("error in closing body in open_meteo.get")
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			err = NotFoundErr
+		case http.StatusTooManyRequests:
+			err = RateLimitedErr
+		default:
+			log.Printf("error in open_meteo.get %v : %v", resp.StatusCode, endpoint)
+			err = UnhandledError
+		}
+
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
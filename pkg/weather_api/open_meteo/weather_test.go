@@ -0,0 +1,260 @@
+package open_meteo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+)
+
+const geocodingBody = `{"results":[
+	{"name":"Springfield","latitude":39.8,"longitude":-89.64,"country":"United States","country_code":"US"},
+	{"name":"Paris","latitude":48.85,"longitude":2.35,"country":"France","country_code":"FR"}
+]}`
+
+// observedAt and partlyCloudy are the current time and weather code of forecastBody
+var (
+	observedAt   = time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	partlyCloudy = 2
+)
+
+const forecastBody = `{"latitude":48.85,"longitude":2.35,"current":{"time":"2025-09-01T12:00","temperature_2m":21.4,"relative_humidity_2m":55,"wind_speed_10m":3.2,"weather_code":2}}`
+
+func setupServer(t *testing.T, geocodingStatus, forecastStatus int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+
+		switch r.URL.Path {
+		case "/search":
+			w.WriteHeader(geocodingStatus)
+			w.Write([]byte(geocodingBody))
+		case "/forecast":
+			assert.NotEmpty(t, r.URL.Query().Get("latitude"))
+			assert.NotEmpty(t, r.URL.Query().Get("longitude"))
+			assert.Equal(t, "ms", r.URL.Query().Get("wind_speed_unit"))
+
+			w.WriteHeader(forecastStatus)
+			w.Write([]byte(forecastBody))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	originalBaseURL, originalGeocodingBaseURL := GetBaseURL(), GetGeocodingBaseURL()
+	SetBaseURL(server.URL + "/forecast")
+	SetGeocodingBaseURL(server.URL + "/search")
+	t.Cleanup(func() {
+		SetBaseURL(originalBaseURL)
+		SetGeocodingBaseURL(originalGeocodingBaseURL)
+	})
+}
+
+func TestFetchWeatherByLocation(t *testing.T) {
+	tests := []struct {
+		name            string
+		cityName        string
+		country         string
+		geocodingStatus int
+		forecastStatus  int
+		expectedResult  *schemata.FetchWeatherResponse
+		expectedError   error
+	}{
+		{
+			name:            "match by country code",
+			cityName:        "Paris",
+			country:         "fr",
+			geocodingStatus: http.StatusOK,
+			forecastStatus:  http.StatusOK,
+			expectedResult: &schemata.FetchWeatherResponse{
+				LocationName:  "Paris",
+				Country:       "FR",
+				Coordinates:   &schemata.Coordinates{Latitude: 48.85, Longitude: 2.35},
+				Temperature:   21.4,
+				Description:   "partly cloudy",
+				ConditionCode: &partlyCloudy,
+				Condition:     schemata.ConditionClouds,
+				Intensity:     schemata.IntensityModerate,
+				Humidity:      55,
+				WindSpeed:     3.2,
+				ObservedAt:    &observedAt,
+				Raw:           json.RawMessage(forecastBody),
+			},
+		},
+		{
+			name:            "match by country name",
+			cityName:        "Paris",
+			country:         "France",
+			geocodingStatus: http.StatusOK,
+			forecastStatus:  http.StatusOK,
+			expectedResult: &schemata.FetchWeatherResponse{
+				LocationName:  "Paris",
+				Country:       "FR",
+				Coordinates:   &schemata.Coordinates{Latitude: 48.85, Longitude: 2.35},
+				Temperature:   21.4,
+				Description:   "partly cloudy",
+				ConditionCode: &partlyCloudy,
+				Condition:     schemata.ConditionClouds,
+				Intensity:     schemata.IntensityModerate,
+				Humidity:      55,
+				WindSpeed:     3.2,
+				ObservedAt:    &observedAt,
+				Raw:           json.RawMessage(forecastBody),
+			},
+		},
+		{
+			name:            "no result in requested country",
+			cityName:        "Paris",
+			country:         "DE",
+			geocodingStatus: http.StatusOK,
+			forecastStatus:  http.StatusOK,
+			expectedError:   NotFoundErr,
+		},
+		{
+			name:            "geocoding server error",
+			cityName:        "Paris",
+			country:         "FR",
+			geocodingStatus: http.StatusInternalServerError,
+			forecastStatus:  http.StatusOK,
+			expectedError:   UnhandledError,
+		},
+		{
+			name:            "geocoding rate limited",
+			cityName:        "Paris",
+			country:         "FR",
+			geocodingStatus: http.StatusTooManyRequests,
+			forecastStatus:  http.StatusOK,
+			expectedError:   RateLimitedErr,
+		},
+		{
+			name:            "forecast server error",
+			cityName:        "Paris",
+			country:         "FR",
+			geocodingStatus: http.StatusOK,
+			forecastStatus:  http.StatusBadGateway,
+			expectedError:   UnhandledError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupServer(t, tt.geocodingStatus, tt.forecastStatus)
+
+			result, err := FetchWeatherByLocation(context.Background(), schemata.Location{CityName: tt.cityName, Country: tt.country}, conf.Config{})
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFetchWeatherByLocation_Coordinates(t *testing.T) {
+	// geocoding failing proves it isn't queried when coordinates are given
+	setupServer(t, http.StatusInternalServerError, http.StatusOK)
+
+	location := schemata.Location{CityName: "Paris", Country: "FR", Coordinates: &schemata.Coordinates{Latitude: 48.85, Longitude: 2.35}}
+	result, err := FetchWeatherByLocation(context.Background(), location, conf.Config{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &schemata.FetchWeatherResponse{
+		LocationName:  "Paris",
+		Country:       "FR",
+		Coordinates:   &schemata.Coordinates{Latitude: 48.85, Longitude: 2.35},
+		Temperature:   21.4,
+		Description:   "partly cloudy",
+		ConditionCode: &partlyCloudy,
+		Condition:     schemata.ConditionClouds,
+		Intensity:     schemata.IntensityModerate,
+		Humidity:      55,
+		WindSpeed:     3.2,
+		ObservedAt:    &observedAt,
+		Raw:           json.RawMessage(forecastBody),
+	}, result)
+}
+
+func TestFetchWeatherByLocation_EmptyGeocodingResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	originalGeocodingBaseURL := GetGeocodingBaseURL()
+	SetGeocodingBaseURL(server.URL)
+	defer SetGeocodingBaseURL(originalGeocodingBaseURL)
+
+	result, err := FetchWeatherByLocation(context.Background(), schemata.Location{CityName: "Atlantis", Country: ""}, conf.Config{})
+
+	assert.Equal(t, NotFoundErr, err)
+	assert.Nil(t, result)
+}
+
+func TestProvider(t *testing.T) {
+	setupServer(t, http.StatusOK, http.StatusOK)
+	provider := NewProvider(conf.Config{})
+
+	assert.Equal(t, "OpenMeteo", provider.Name())
+
+	result, err := provider.FetchCurrent(context.Background(), schemata.Location{CityName: "Paris", Country: "FR"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Paris", result.LocationName)
+
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+
+	assert.True(t, provider.IsTransient(UnhandledError))
+	assert.True(t, provider.IsTransient(RateLimitedErr))
+	assert.False(t, provider.IsTransient(NotFoundErr))
+}
+
+func TestProvider_Limit(t *testing.T) {
+	setupServer(t, http.StatusOK, http.StatusOK)
+	provider := NewProvider(conf.Config{})
+
+	acquired := 0
+	provider.Limit(func(ctx context.Context) error {
+		acquired++
+		return nil
+	})
+
+	_, err := provider.FetchCurrent(context.Background(), schemata.Location{CityName: "Paris", Country: "FR"})
+	assert.NoError(t, err)
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+
+	// geocoding and forecast requests of both calls are accounted for
+	assert.Equal(t, 4, acquired)
+}
+
+func TestProvider_Geocode(t *testing.T) {
+	setupServer(t, http.StatusOK, http.StatusOK)
+
+	result, err := NewProvider(conf.Config{}).Geocode(context.Background(), schemata.Location{CityName: "Paris", Country: "fr"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []schemata.Place{{Name: "Paris", Country: "FR", Latitude: 48.85, Longitude: 2.35}}, result)
+}
+
+func TestProvider_MapCurrent(t *testing.T) {
+	result, err := NewProvider(conf.Config{}).MapCurrent(json.RawMessage(forecastBody))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &schemata.FetchWeatherResponse{
+		Coordinates:   &schemata.Coordinates{Latitude: 48.85, Longitude: 2.35},
+		Temperature:   21.4,
+		Description:   "partly cloudy",
+		ConditionCode: &partlyCloudy,
+		Condition:     schemata.ConditionClouds,
+		Intensity:     schemata.IntensityModerate,
+		Humidity:      55,
+		WindSpeed:     3.2,
+		ObservedAt:    &observedAt,
+		Raw:           json.RawMessage(forecastBody),
+	}, result)
+}
@@ -0,0 +1,65 @@
+package open_weather
+
+import (
+	"context"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var airPollutionBaseURL = "https://api.openweathermap.org/data/2.5/air_pollution"
+
+// SetAirPollutionBaseURL allows setting the air pollution base URL for testing purposes
+func SetAirPollutionBaseURL(url string) {
+	airPollutionBaseURL = url
+}
+
+// GetAirPollutionBaseURL returns the current air pollution base URL
+func GetAirPollutionBaseURL() string {
+	return airPollutionBaseURL
+}
+
+// FetchAirQualityByLocation fetches the current air quality of a location. The air pollution API is only
+// queried by coordinates, so a location without them is geocoded first.
+func FetchAirQualityByLocation(ctx context.Context, location schemata.Location, config conf.Config) (*schemata.FetchAirQualityResponse, error) {
+	return NewProvider(config).FetchAirQuality(ctx, location)
+}
+
+func (p Provider) FetchAirQuality(ctx context.Context, location schemata.Location) (*schemata.FetchAirQualityResponse, error) {
+	location, err := p.withCoordinates(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	var owResp AirPollutionResponse
+	if err := p.get(ctx, GetAirPollutionBaseURL(), locationQuery(schemata.Location{Coordinates: location.Coordinates}), &owResp); err != nil {
+		return nil, err
+	}
+
+	if len(owResp.List) == 0 {
+		return nil, NotFoundErr
+	}
+
+	dto := mapAirPollutionResponseToFetchAirQualityResponse(owResp)
+	dto.LocationName, dto.Country = location.CityName, location.Country
+
+	return &dto, nil
+}
+
+// withCoordinates returns location with its coordinates, named after the place geocoded for it when it
+// had none, for the APIs only queried by coordinates.
+func (p Provider) withCoordinates(ctx context.Context, location schemata.Location) (schemata.Location, error) {
+	if location.Coordinates != nil {
+		return location, nil
+	}
+
+	places, err := p.Geocode(ctx, location)
+	if err != nil {
+		return location, err
+	}
+
+	location.CityName, location.Country = places[0].Name, places[0].Country
+	location.Coordinates = &schemata.Coordinates{Latitude: places[0].Latitude, Longitude: places[0].Longitude}
+
+	return location, nil
+}
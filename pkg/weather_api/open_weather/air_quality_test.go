@@ -0,0 +1,137 @@
+package open_weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const airPollutionPayload = `{
+	"coord": {"lon": -0.1278, "lat": 51.5074},
+	"list": [{
+		"dt": 1756728000,
+		"main": {"aqi": 2},
+		"components": {"co": 201.94, "no": 0.02, "no2": 0.77, "o3": 68.66, "so2": 0.64, "pm2_5": 0.5, "pm10": 0.54, "nh3": 0.12}
+	}]
+}`
+
+const londonGeocodingPayload = `[{"name": "London", "lat": 51.5074, "lon": -0.1278, "country": "GB"}]`
+
+func TestFetchAirQualityByLocation(t *testing.T) {
+	tests := []struct {
+		name           string
+		location       schemata.Location
+		statusCode     int
+		payload        string
+		expectedName   string
+		expectedResult *schemata.FetchAirQualityResponse
+		expectedError  error
+	}{
+		{
+			name:       "geocoded city",
+			location:   schemata.Location{CityName: "London", Country: "GB"},
+			statusCode: http.StatusOK,
+			payload:    airPollutionPayload,
+			expectedResult: &schemata.FetchAirQualityResponse{
+				LocationName: "London",
+				Country:      "GB",
+				Latitude:     51.5074,
+				Longitude:    -0.1278,
+				AQI:          2,
+				CO:           ptr(201.94),
+				NO:           ptr(0.02),
+				NO2:          ptr(0.77),
+				O3:           ptr(68.66),
+				SO2:          ptr(0.64),
+				PM25:         ptr(0.5),
+				PM10:         ptr(0.54),
+				NH3:          ptr(0.12),
+				ObservedAt:   ptr(time.Unix(1756728000, 0).UTC()),
+			},
+		},
+		{
+			name:          "no air quality reported",
+			location:      schemata.Location{CityName: "London", Country: "GB"},
+			statusCode:    http.StatusOK,
+			payload:       `{"coord": {"lon": -0.1278, "lat": 51.5074}, "list": []}`,
+			expectedError: NotFoundErr,
+		},
+		{
+			name:          "rate limited",
+			location:      schemata.Location{CityName: "London", Country: "GB"},
+			statusCode:    http.StatusTooManyRequests,
+			expectedError: RateLimitedErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "test-api-key", r.URL.Query().Get("appid"))
+
+				if r.URL.Path == "/geo" {
+					assert.Equal(t, "London,GB", r.URL.Query().Get("q"))
+					w.Write([]byte(londonGeocodingPayload))
+					return
+				}
+
+				assert.Equal(t, "51.5074", r.URL.Query().Get("lat"))
+				assert.Equal(t, "-0.1278", r.URL.Query().Get("lon"))
+
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.payload))
+			}))
+			defer server.Close()
+
+			originalBaseURL, originalGeocodingBaseURL := GetAirPollutionBaseURL(), GetGeocodingBaseURL()
+			SetAirPollutionBaseURL(server.URL + "/air_pollution")
+			SetGeocodingBaseURL(server.URL + "/geo")
+			defer SetAirPollutionBaseURL(originalBaseURL)
+			defer SetGeocodingBaseURL(originalGeocodingBaseURL)
+
+			config := conf.Config{}
+			config.OpenWeather.ApiKey = "test-api-key"
+
+			result, err := FetchAirQualityByLocation(context.Background(), tt.location, config)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestProvider_FetchAirQuality(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("q"), "coordinates aren't geocoded")
+		w.Write([]byte(airPollutionPayload))
+	}))
+	defer server.Close()
+
+	originalBaseURL := GetAirPollutionBaseURL()
+	SetAirPollutionBaseURL(server.URL)
+	defer SetAirPollutionBaseURL(originalBaseURL)
+
+	provider := NewProvider(conf.Config{})
+	result, err := provider.FetchAirQuality(context.Background(), schemata.Location{
+		CityName:    "London",
+		Country:     "GB",
+		Coordinates: &schemata.Coordinates{Latitude: 51.5074, Longitude: -0.1278},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "London", result.LocationName)
+	assert.Equal(t, 2, result.AQI)
+}
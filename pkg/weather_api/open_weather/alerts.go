@@ -0,0 +1,50 @@
+package open_weather
+
+import (
+	"context"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var oneCallBaseURL = "https://api.openweathermap.org/data/3.0/onecall"
+
+// oneCallAlertsOnly excludes every part of the One Call answer but alerts
+const oneCallAlertsOnly = "current,minutely,hourly,daily"
+
+// SetOneCallBaseURL allows setting the One Call base URL for testing purposes
+func SetOneCallBaseURL(url string) {
+	oneCallBaseURL = url
+}
+
+// GetOneCallBaseURL returns the current One Call base URL
+func GetOneCallBaseURL() string {
+	return oneCallBaseURL
+}
+
+// FetchAlertsByLocation fetches the national weather alerts in effect or upcoming at a location through the
+// One Call API, which needs its own subscription. It is only queried by coordinates, so a location
+// without them is geocoded first.
+func FetchAlertsByLocation(ctx context.Context, location schemata.Location, config conf.Config) (*schemata.FetchAlertsResponse, error) {
+	return NewProvider(config).FetchAlerts(ctx, location)
+}
+
+func (p Provider) FetchAlerts(ctx context.Context, location schemata.Location) (*schemata.FetchAlertsResponse, error) {
+	location, err := p.withCoordinates(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	query := locationQuery(schemata.Location{Coordinates: location.Coordinates})
+	query.Set("exclude", oneCallAlertsOnly)
+
+	var owResp OneCallResponse
+	if err := p.get(ctx, GetOneCallBaseURL(), query, &owResp); err != nil {
+		return nil, err
+	}
+
+	dto := mapOneCallResponseToFetchAlertsResponse(owResp)
+	dto.LocationName, dto.Country = location.CityName, location.Country
+
+	return &dto, nil
+}
@@ -0,0 +1,165 @@
+package open_weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oneCallAlertsPayload = `{
+	"lat": 51.5074,
+	"lon": -0.1278,
+	"timezone": "Europe/London",
+	"alerts": [{
+		"sender_name": "Met Office",
+		"event": "Yellow wind warning",
+		"start": 1756728000,
+		"end": 1756771200,
+		"description": "Strong winds may cause some disruption to travel.",
+		"tags": ["Wind"]
+	}]
+}`
+
+func TestFetchAlertsByLocation(t *testing.T) {
+	tests := []struct {
+		name           string
+		statusCode     int
+		payload        string
+		expectedResult *schemata.FetchAlertsResponse
+		expectedError  error
+	}{
+		{
+			name:       "alerts in effect",
+			statusCode: http.StatusOK,
+			payload:    oneCallAlertsPayload,
+			expectedResult: &schemata.FetchAlertsResponse{
+				LocationName: "London",
+				Country:      "GB",
+				Latitude:     51.5074,
+				Longitude:    -0.1278,
+				Alerts: []schemata.Alert{{
+					Sender:      "Met Office",
+					Event:       "Yellow wind warning",
+					Severity:    schemata.SeverityModerate,
+					Start:       time.Unix(1756728000, 0).UTC(),
+					End:         time.Unix(1756771200, 0).UTC(),
+					Description: "Strong winds may cause some disruption to travel.",
+					Tags:        []string{"Wind"},
+				}},
+			},
+		},
+		{
+			name:       "no alerts",
+			statusCode: http.StatusOK,
+			payload:    `{"lat": 51.5074, "lon": -0.1278, "timezone": "Europe/London"}`,
+			expectedResult: &schemata.FetchAlertsResponse{
+				LocationName: "London",
+				Country:      "GB",
+				Latitude:     51.5074,
+				Longitude:    -0.1278,
+				Alerts:       []schemata.Alert{},
+			},
+		},
+		{
+			name:          "rate limited",
+			statusCode:    http.StatusTooManyRequests,
+			expectedError: RateLimitedErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "test-api-key", r.URL.Query().Get("appid"))
+
+				if r.URL.Path == "/geo" {
+					assert.Equal(t, "London,GB", r.URL.Query().Get("q"))
+					w.Write([]byte(londonGeocodingPayload))
+					return
+				}
+
+				assert.Equal(t, "51.5074", r.URL.Query().Get("lat"))
+				assert.Equal(t, "-0.1278", r.URL.Query().Get("lon"))
+				assert.Equal(t, "current,minutely,hourly,daily", r.URL.Query().Get("exclude"))
+
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.payload))
+			}))
+			defer server.Close()
+
+			originalBaseURL, originalGeocodingBaseURL := GetOneCallBaseURL(), GetGeocodingBaseURL()
+			SetOneCallBaseURL(server.URL + "/onecall")
+			SetGeocodingBaseURL(server.URL + "/geo")
+			defer SetOneCallBaseURL(originalBaseURL)
+			defer SetGeocodingBaseURL(originalGeocodingBaseURL)
+
+			config := conf.Config{}
+			config.OpenWeather.ApiKey = "test-api-key"
+
+			result, err := FetchAlertsByLocation(context.Background(), schemata.Location{CityName: "London", Country: "GB"}, config)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestSeverityOf(t *testing.T) {
+	tests := []struct {
+		event    string
+		expected schemata.Severity
+	}{
+		{event: "Red extreme heat warning", expected: schemata.SeverityExtreme},
+		{event: "Orange rain warning", expected: schemata.SeveritySevere},
+		{event: "Yellow wind warning", expected: schemata.SeverityModerate},
+		{event: "Severe Thunderstorm Watch", expected: schemata.SeveritySevere},
+		{event: "Flood Watch", expected: schemata.SeverityModerate},
+		{event: "Heat Advisory", expected: schemata.SeverityMinor},
+		{event: "Red Flag Warning", expected: schemata.SeveritySevere},
+		{event: "Coastal event", expected: schemata.SeverityUnknown},
+		{event: "Considered hazard", expected: schemata.SeverityUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			assert.Equal(t, tt.expected, severityOf(tt.event))
+		})
+	}
+}
+
+func TestProvider_FetchAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("q"), "coordinates aren't geocoded")
+		w.Write([]byte(oneCallAlertsPayload))
+	}))
+	defer server.Close()
+
+	originalBaseURL := GetOneCallBaseURL()
+	SetOneCallBaseURL(server.URL)
+	defer SetOneCallBaseURL(originalBaseURL)
+
+	provider := NewProvider(conf.Config{})
+	result, err := provider.FetchAlerts(context.Background(), schemata.Location{
+		CityName:    "London",
+		Country:     "GB",
+		Coordinates: &schemata.Coordinates{Latitude: 51.5074, Longitude: -0.1278},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "London", result.LocationName)
+	require.Len(t, result.Alerts, 1)
+	assert.Equal(t, "Met Office", result.Alerts[0].Sender)
+}
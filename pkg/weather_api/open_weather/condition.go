@@ -0,0 +1,67 @@
+package open_weather
+
+import "github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+
+type condition struct {
+	condition schemata.Condition
+	intensity schemata.Intensity
+}
+
+// conditions maps OpenWeather condition codes, https://openweathermap.org/weather-conditions, to conditions.
+var conditions = map[int]condition{
+	200: {schemata.ConditionThunderstorm, schemata.IntensityLight},
+	201: {schemata.ConditionThunderstorm, schemata.IntensityModerate},
+	202: {schemata.ConditionThunderstorm, schemata.IntensityHeavy},
+	210: {schemata.ConditionThunderstorm, schemata.IntensityLight},
+	211: {schemata.ConditionThunderstorm, schemata.IntensityModerate},
+	212: {schemata.ConditionThunderstorm, schemata.IntensityHeavy},
+	221: {schemata.ConditionThunderstorm, schemata.IntensityHeavy},
+	230: {schemata.ConditionThunderstorm, schemata.IntensityLight},
+	231: {schemata.ConditionThunderstorm, schemata.IntensityModerate},
+	232: {schemata.ConditionThunderstorm, schemata.IntensityHeavy},
+	300: {schemata.ConditionDrizzle, schemata.IntensityLight},
+	301: {schemata.ConditionDrizzle, schemata.IntensityModerate},
+	302: {schemata.ConditionDrizzle, schemata.IntensityHeavy},
+	310: {schemata.ConditionDrizzle, schemata.IntensityLight},
+	311: {schemata.ConditionDrizzle, schemata.IntensityModerate},
+	312: {schemata.ConditionDrizzle, schemata.IntensityHeavy},
+	313: {schemata.ConditionDrizzle, schemata.IntensityModerate},
+	314: {schemata.ConditionDrizzle, schemata.IntensityHeavy},
+	321: {schemata.ConditionDrizzle, schemata.IntensityModerate},
+	500: {schemata.ConditionRain, schemata.IntensityLight},
+	501: {schemata.ConditionRain, schemata.IntensityModerate},
+	502: {schemata.ConditionRain, schemata.IntensityHeavy},
+	503: {schemata.ConditionRain, schemata.IntensityHeavy},
+	504: {schemata.ConditionRain, schemata.IntensityHeavy},
+	511: {schemata.ConditionRain, schemata.IntensityModerate},
+	520: {schemata.ConditionRain, schemata.IntensityLight},
+	521: {schemata.ConditionRain, schemata.IntensityModerate},
+	522: {schemata.ConditionRain, schemata.IntensityHeavy},
+	531: {schemata.ConditionRain, schemata.IntensityModerate},
+	600: {schemata.ConditionSnow, schemata.IntensityLight},
+	601: {schemata.ConditionSnow, schemata.IntensityModerate},
+	602: {schemata.ConditionSnow, schemata.IntensityHeavy},
+	611: {schemata.ConditionSleet, schemata.IntensityModerate},
+	612: {schemata.ConditionSleet, schemata.IntensityLight},
+	613: {schemata.ConditionSleet, schemata.IntensityModerate},
+	615: {schemata.ConditionSleet, schemata.IntensityLight},
+	616: {schemata.ConditionSleet, schemata.IntensityModerate},
+	620: {schemata.ConditionSnow, schemata.IntensityLight},
+	621: {schemata.ConditionSnow, schemata.IntensityModerate},
+	622: {schemata.ConditionSnow, schemata.IntensityHeavy},
+	701: {schemata.ConditionFog, schemata.IntensityLight},
+	711: {schemata.ConditionFog, schemata.IntensityModerate},
+	721: {schemata.ConditionFog, schemata.IntensityLight},
+	731: {schemata.ConditionDust, schemata.IntensityModerate},
+	741: {schemata.ConditionFog, schemata.IntensityModerate},
+	751: {schemata.ConditionDust, schemata.IntensityModerate},
+	761: {schemata.ConditionDust, schemata.IntensityModerate},
+	762: {schemata.ConditionDust, schemata.IntensityHeavy},
+	771: {schemata.ConditionSquall, ""},
+	781: {schemata.ConditionTornado, ""},
+	800: {schemata.ConditionClear, ""},
+	801: {schemata.ConditionClouds, schemata.IntensityLight},
+	802: {schemata.ConditionClouds, schemata.IntensityLight},
+	803: {schemata.ConditionClouds, schemata.IntensityModerate},
+	804: {schemata.ConditionClouds, schemata.IntensityHeavy},
+}
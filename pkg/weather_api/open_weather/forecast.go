@@ -0,0 +1,36 @@
+package open_weather
+
+import (
+	"context"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var forecastBaseURL = "https://api.openweathermap.org/data/2.5/forecast"
+
+// SetForecastBaseURL allows setting the forecast base URL for testing purposes
+func SetForecastBaseURL(url string) {
+	forecastBaseURL = url
+}
+
+// GetForecastBaseURL returns the current forecast base URL
+func GetForecastBaseURL() string {
+	return forecastBaseURL
+}
+
+// FetchForecastByLocation fetches the 5 day forecast of a location in 3 hour steps.
+func FetchForecastByLocation(ctx context.Context, location schemata.Location, config conf.Config) (*schemata.FetchForecastResponse, error) {
+	return NewProvider(config).FetchForecast(ctx, location)
+}
+
+func (p Provider) FetchForecast(ctx context.Context, location schemata.Location) (*schemata.FetchForecastResponse, error) {
+	var owResp ForecastResponse
+	if err := p.get(ctx, GetForecastBaseURL(), locationQuery(location), &owResp); err != nil {
+		return nil, err
+	}
+
+	dto := mapOpenWeatherForecastResponseToFetchForecastResponse(owResp)
+
+	return &dto, nil
+}
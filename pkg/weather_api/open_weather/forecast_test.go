@@ -0,0 +1,107 @@
+package open_weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const forecastPayload = `{
+	"list": [
+		{"dt": 1756728000, "main": {"temp": 18.2, "humidity": 70}, "weather": [{"id": 803, "main": "Clouds", "description": "broken clouds"}], "wind": {"speed": 4.1}},
+		{"dt": 1756738800, "main": {"temp": 16.9, "humidity": 78}, "weather": [], "wind": {"speed": 3.5}}
+	],
+	"city": {"name": "London", "country": "GB"}
+}`
+
+func TestFetchForecastByLocation(t *testing.T) {
+	brokenClouds := 803
+
+	tests := []struct {
+		name           string
+		statusCode     int
+		expectedResult *schemata.FetchForecastResponse
+		expectedError  error
+	}{
+		{
+			name:       "successful forecast fetch",
+			statusCode: http.StatusOK,
+			expectedResult: &schemata.FetchForecastResponse{
+				LocationName: "London",
+				Country:      "GB",
+				Entries: []schemata.ForecastEntry{
+					{Time: time.Unix(1756728000, 0).UTC(), Temperature: 18.2, Description: "broken clouds", Humidity: 70, WindSpeed: 4.1, ConditionCode: &brokenClouds},
+					{Time: time.Unix(1756738800, 0).UTC(), Temperature: 16.9, Humidity: 78, WindSpeed: 3.5},
+				},
+			},
+		},
+		{
+			name:          "city not found",
+			statusCode:    http.StatusNotFound,
+			expectedError: NotFoundErr,
+		},
+		{
+			name:          "rate limited",
+			statusCode:    http.StatusTooManyRequests,
+			expectedError: RateLimitedErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "London,GB", r.URL.Query().Get("q"))
+				assert.Equal(t, "test-api-key", r.URL.Query().Get("appid"))
+				assert.Equal(t, "metric", r.URL.Query().Get("units"))
+
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode == http.StatusOK {
+					w.Write([]byte(forecastPayload))
+				}
+			}))
+			defer server.Close()
+
+			originalBaseURL := GetForecastBaseURL()
+			SetForecastBaseURL(server.URL)
+			defer SetForecastBaseURL(originalBaseURL)
+
+			config := conf.Config{}
+			config.OpenWeather.ApiKey = "test-api-key"
+
+			result, err := FetchForecastByLocation(context.Background(), schemata.Location{CityName: "London", Country: "GB"}, config)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestProvider_FetchForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(forecastPayload))
+	}))
+	defer server.Close()
+
+	originalBaseURL := GetForecastBaseURL()
+	SetForecastBaseURL(server.URL)
+	defer SetForecastBaseURL(originalBaseURL)
+
+	provider := NewProvider(conf.Config{})
+	result, err := provider.FetchForecast(context.Background(), schemata.Location{CityName: "London", Country: ""})
+
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+}
@@ -0,0 +1,82 @@
+package open_weather
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+var (
+	geocodingBaseURL        = "https://api.openweathermap.org/geo/1.0/direct"
+	reverseGeocodingBaseURL = "https://api.openweathermap.org/geo/1.0/reverse"
+)
+
+// geocodingCandidates is the number of places asked to the geocoding API, its maximum.
+const geocodingCandidates = 5
+
+// SetGeocodingBaseURL allows setting the geocoding base URL for testing purposes
+func SetGeocodingBaseURL(url string) {
+	geocodingBaseURL = url
+}
+
+// GetGeocodingBaseURL returns the current geocoding base URL
+func GetGeocodingBaseURL() string {
+	return geocodingBaseURL
+}
+
+// SetReverseGeocodingBaseURL allows setting the reverse geocoding base URL for testing purposes
+func SetReverseGeocodingBaseURL(url string) {
+	reverseGeocodingBaseURL = url
+}
+
+// GetReverseGeocodingBaseURL returns the current reverse geocoding base URL
+func GetReverseGeocodingBaseURL() string {
+	return reverseGeocodingBaseURL
+}
+
+// Geocode returns the places named location.CityName, in location.Country when it is set,
+// in the order OpenWeather ranks them.
+func Geocode(ctx context.Context, location schemata.Location, config conf.Config) ([]schemata.Place, error) {
+	return NewProvider(config).Geocode(ctx, location)
+}
+
+func (p Provider) Geocode(ctx context.Context, location schemata.Location) ([]schemata.Place, error) {
+	query := locationQuery(schemata.Location{CityName: location.CityName, Country: location.Country})
+	query.Set("limit", fmt.Sprint(geocodingCandidates))
+
+	return p.geocode(ctx, GetGeocodingBaseURL(), query)
+}
+
+// ReverseGeocode returns the places nearest to coordinates, nearest first.
+func ReverseGeocode(ctx context.Context, coordinates schemata.Coordinates, config conf.Config) ([]schemata.Place, error) {
+	return NewProvider(config).ReverseGeocode(ctx, coordinates)
+}
+
+func (p Provider) ReverseGeocode(ctx context.Context, coordinates schemata.Coordinates) ([]schemata.Place, error) {
+	query := locationQuery(schemata.Location{Coordinates: &coordinates})
+	query.Set("limit", fmt.Sprint(geocodingCandidates))
+
+	return p.geocode(ctx, GetReverseGeocodingBaseURL(), query)
+}
+
+func (p Provider) geocode(ctx context.Context, endpoint string, query url.Values) ([]schemata.Place, error) {
+	var results []GeocodingResult
+	if err := p.get(ctx, endpoint, query, &results); err != nil {
+		return nil, err
+	}
+
+	// unlike the weather API, unknown places are answered with an empty array
+	if len(results) == 0 {
+		return nil, NotFoundErr
+	}
+
+	places := make([]schemata.Place, 0, len(results))
+	for _, result := range results {
+		places = append(places, mapGeocodingResultToPlace(result))
+	}
+
+	return places, nil
+}
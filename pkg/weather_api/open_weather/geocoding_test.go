@@ -0,0 +1,86 @@
+package open_weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeocode(t *testing.T) {
+	tests := []struct {
+		name           string
+		payload        string
+		statusCode     int
+		expectedResult []schemata.Place
+		expectedError  error
+	}{
+		{
+			name:       "places found",
+			payload:    `[{"name":"London","local_names":{"fr":"Londres"},"lat":51.5073,"lon":-0.1276,"country":"GB","state":"England"},{"name":"London","lat":42.9834,"lon":-81.233,"country":"CA","state":"Ontario"}]`,
+			statusCode: http.StatusOK,
+			expectedResult: []schemata.Place{
+				{Name: "London", Country: "GB", State: "England", Latitude: 51.5073, Longitude: -0.1276},
+				{Name: "London", Country: "CA", State: "Ontario", Latitude: 42.9834, Longitude: -81.233},
+			},
+		},
+		{
+			name:          "no place found",
+			payload:       `[]`,
+			statusCode:    http.StatusOK,
+			expectedError: NotFoundErr,
+		},
+		{
+			name:          "server error",
+			payload:       `{}`,
+			statusCode:    http.StatusInternalServerError,
+			expectedError: UnhandledError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "London", r.URL.Query().Get("q"))
+				assert.Equal(t, "5", r.URL.Query().Get("limit"))
+
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.payload))
+			}))
+			defer server.Close()
+
+			originalGeocodingBaseURL := GetGeocodingBaseURL()
+			SetGeocodingBaseURL(server.URL)
+			defer SetGeocodingBaseURL(originalGeocodingBaseURL)
+
+			result, err := NewProvider(conf.Config{}).Geocode(context.Background(), schemata.Location{CityName: "London"})
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestReverseGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("q"))
+		assert.Equal(t, "51.5098", r.URL.Query().Get("lat"))
+		assert.Equal(t, "-0.118", r.URL.Query().Get("lon"))
+
+		w.Write([]byte(`[{"name":"London","lat":51.5073,"lon":-0.1276,"country":"GB","state":"England"}]`))
+	}))
+	defer server.Close()
+
+	originalReverseGeocodingBaseURL := GetReverseGeocodingBaseURL()
+	SetReverseGeocodingBaseURL(server.URL)
+	defer SetReverseGeocodingBaseURL(originalReverseGeocodingBaseURL)
+
+	result, err := NewProvider(conf.Config{}).ReverseGeocode(context.Background(), schemata.Coordinates{Latitude: 51.5098, Longitude: -0.118})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []schemata.Place{{Name: "London", Country: "GB", State: "England", Latitude: 51.5073, Longitude: -0.1276}}, result)
+}
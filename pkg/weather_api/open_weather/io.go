@@ -2,20 +2,124 @@ package open_weather
 
 type Response struct {
 	Name string `json:"name"`
-	Sys  struct {
+	// Coord is nil when the response doesn't locate the weather
+	Coord *struct {
+		Lon float64 `json:"lon"`
+		Lat float64 `json:"lat"`
+	} `json:"coord"`
+	Sys struct {
 		Country string `json:"country"`
+		Sunrise int64  `json:"sunrise"`
+		Sunset  int64  `json:"sunset"`
 	} `json:"sys"`
+	Main struct {
+		Temp      float64  `json:"temp"`
+		FeelsLike *float64 `json:"feels_like"`
+		TempMin   *float64 `json:"temp_min"`
+		TempMax   *float64 `json:"temp_max"`
+		Pressure  int      `json:"pressure"`
+		Humidity  int      `json:"humidity"`
+	} `json:"main"`
+	Weather []struct {
+		// ID is the condition code of https://openweathermap.org/weather-conditions
+		ID          int    `json:"id"`
+		Main        string `json:"main"`
+		Description string `json:"description"`
+	} `json:"weather"`
+	Wind struct {
+		Speed float64  `json:"speed"`
+		Deg   *int     `json:"deg"`
+		Gust  *float64 `json:"gust"`
+	} `json:"wind"`
+	Visibility *int `json:"visibility"`
+	Clouds     struct {
+		All *int `json:"all"`
+	} `json:"clouds"`
+	Rain *Precipitation `json:"rain"`
+	Snow *Precipitation `json:"snow"`
+	// Dt is the unix time the weather was observed at
+	Dt int64 `json:"dt"`
+}
+
+// Precipitation holds the volumes, in mm, fallen over the last hour and 3 hours
+type Precipitation struct {
+	OneHour   *float64 `json:"1h"`
+	ThreeHour *float64 `json:"3h"`
+}
+
+// GeocodingResult is one place of the array answered by the geocoding API
+type GeocodingResult struct {
+	Name       string            `json:"name"`
+	LocalNames map[string]string `json:"local_names"`
+	Lat        float64           `json:"lat"`
+	Lon        float64           `json:"lon"`
+	Country    string            `json:"country"`
+	State      string            `json:"state"`
+}
+
+type ForecastResponse struct {
+	List []ForecastItem `json:"list"`
+	City struct {
+		Name    string `json:"name"`
+		Country string `json:"country"`
+	} `json:"city"`
+}
+
+// ForecastItem is one 3 hour step of a ForecastResponse
+type ForecastItem struct {
+	// Dt is the unix time the step starts at
+	Dt   int64 `json:"dt"`
 	Main struct {
 		Temp     float64 `json:"temp"`
-		Pressure int     `json:"pressure"`
 		Humidity int     `json:"humidity"`
 	} `json:"main"`
 	Weather []struct {
+		ID          int    `json:"id"`
 		Main        string `json:"main"`
 		Description string `json:"description"`
 	} `json:"weather"`
 	Wind struct {
 		Speed float64 `json:"speed"`
-		Deg   int     `json:"deg"`
 	} `json:"wind"`
 }
+
+// AirPollutionResponse is answered by the air pollution API, its list holding the current air quality
+type AirPollutionResponse struct {
+	Coord struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+	List []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			// Aqi is the air quality index, from 1 (good) to 5 (very poor)
+			Aqi int `json:"aqi"`
+		} `json:"main"`
+		// Components are the concentrations of pollutants in μg/m3
+		Components struct {
+			Co   *float64 `json:"co"`
+			No   *float64 `json:"no"`
+			No2  *float64 `json:"no2"`
+			O3   *float64 `json:"o3"`
+			So2  *float64 `json:"so2"`
+			Pm25 *float64 `json:"pm2_5"`
+			Pm10 *float64 `json:"pm10"`
+			Nh3  *float64 `json:"nh3"`
+		} `json:"components"`
+	} `json:"list"`
+}
+
+// OneCallResponse is the One Call API answer when everything but alerts is excluded. Alerts is
+// missing when there are none.
+type OneCallResponse struct {
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Alerts []struct {
+		SenderName  string   `json:"sender_name"`
+		Event       string   `json:"event"`
+		Start       int64    `json:"start"`
+		End         int64    `json:"end"`
+		Description string   `json:"description"`
+		Tags        []string `json:"tags"`
+	} `json:"alerts"`
+}
@@ -1,6 +1,8 @@
 package open_weather
 
 import (
+	"time"
+
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 )
 
@@ -12,10 +14,131 @@ func mapOpenWeatherResponseToFetchWeatherResponse(owResp Response) schemata.Fetc
 		Description:  "",
 		Humidity:     owResp.Main.Humidity,
 		WindSpeed:    owResp.Wind.Speed,
+		WindDeg:      owResp.Wind.Deg,
+		WindGust:     owResp.Wind.Gust,
+		FeelsLike:    owResp.Main.FeelsLike,
+		TempMin:      owResp.Main.TempMin,
+		TempMax:      owResp.Main.TempMax,
+		Visibility:   owResp.Visibility,
+		Clouds:       owResp.Clouds.All,
+		Sunrise:      unixTime(owResp.Sys.Sunrise),
+		Sunset:       unixTime(owResp.Sys.Sunset),
+		ObservedAt:   unixTime(owResp.Dt),
 	}
 
 	if len(owResp.Weather) > 0 {
 		resp.Description = owResp.Weather[0].Description
+		resp.ConditionCode = &owResp.Weather[0].ID
+		resp.Condition = conditions[owResp.Weather[0].ID].condition
+		resp.Intensity = conditions[owResp.Weather[0].ID].intensity
+	}
+
+	if owResp.Coord != nil {
+		resp.Coordinates = &schemata.Coordinates{Latitude: owResp.Coord.Lat, Longitude: owResp.Coord.Lon}
+	}
+
+	// pressure is never 0 hPa, so 0 means it wasn't reported
+	if owResp.Main.Pressure != 0 {
+		resp.Pressure = &owResp.Main.Pressure
+	}
+
+	if owResp.Rain != nil {
+		resp.Rain1h, resp.Rain3h = owResp.Rain.OneHour, owResp.Rain.ThreeHour
+	}
+	if owResp.Snow != nil {
+		resp.Snow1h, resp.Snow3h = owResp.Snow.OneHour, owResp.Snow.ThreeHour
+	}
+
+	return resp
+}
+
+// unixTime returns the UTC time of the unix timestamp seconds, nil when it is 0, meaning unreported.
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+
+	t := time.Unix(seconds, 0).UTC()
+
+	return &t
+}
+
+func mapGeocodingResultToPlace(result GeocodingResult) schemata.Place {
+	return schemata.Place{
+		Name:      result.Name,
+		Country:   result.Country,
+		State:     result.State,
+		Latitude:  result.Lat,
+		Longitude: result.Lon,
+	}
+}
+
+// mapAirPollutionResponseToFetchAirQualityResponse maps the first entry of owResp's list, which must not be
+// empty. The air pollution API doesn't name the place, so LocationName and Country are left empty.
+func mapAirPollutionResponseToFetchAirQualityResponse(owResp AirPollutionResponse) schemata.FetchAirQualityResponse {
+	current := owResp.List[0]
+
+	return schemata.FetchAirQualityResponse{
+		Latitude:   owResp.Coord.Lat,
+		Longitude:  owResp.Coord.Lon,
+		AQI:        current.Main.Aqi,
+		CO:         current.Components.Co,
+		NO:         current.Components.No,
+		NO2:        current.Components.No2,
+		O3:         current.Components.O3,
+		SO2:        current.Components.So2,
+		PM25:       current.Components.Pm25,
+		PM10:       current.Components.Pm10,
+		NH3:        current.Components.Nh3,
+		ObservedAt: unixTime(current.Dt),
+	}
+}
+
+// mapOneCallResponseToFetchAlertsResponse maps the alerts of owResp. The One Call API doesn't name the place,
+// so LocationName and Country are left empty.
+func mapOneCallResponseToFetchAlertsResponse(owResp OneCallResponse) schemata.FetchAlertsResponse {
+	resp := schemata.FetchAlertsResponse{
+		Latitude:  owResp.Lat,
+		Longitude: owResp.Lon,
+		Alerts:    make([]schemata.Alert, 0, len(owResp.Alerts)),
+	}
+
+	for _, alert := range owResp.Alerts {
+		resp.Alerts = append(resp.Alerts, schemata.Alert{
+			Sender:      alert.SenderName,
+			Event:       alert.Event,
+			Severity:    severityOf(alert.Event),
+			Start:       time.Unix(alert.Start, 0).UTC(),
+			End:         time.Unix(alert.End, 0).UTC(),
+			Description: alert.Description,
+			Tags:        alert.Tags,
+		})
+	}
+
+	return resp
+}
+
+func mapOpenWeatherForecastResponseToFetchForecastResponse(owResp ForecastResponse) schemata.FetchForecastResponse {
+	resp := schemata.FetchForecastResponse{
+		LocationName: owResp.City.Name,
+		Country:      owResp.City.Country,
+		Entries:      make([]schemata.ForecastEntry, 0, len(owResp.List)),
+	}
+
+	for _, item := range owResp.List {
+		entry := schemata.ForecastEntry{
+			Time:        time.Unix(item.Dt, 0).UTC(),
+			Temperature: item.Main.Temp,
+			Humidity:    item.Main.Humidity,
+			WindSpeed:   item.Wind.Speed,
+		}
+
+		if len(item.Weather) > 0 {
+			entry.Description = item.Weather[0].Description
+			entry.ConditionCode = &item.Weather[0].ID
+		}
+
+		resp.Entries = append(resp.Entries, entry)
 	}
 
 	return resp
@@ -1,10 +1,13 @@
 package open_weather
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMapOpenWeatherResponseToFetchWeatherResponse(t *testing.T) {
@@ -19,42 +22,55 @@ func TestMapOpenWeatherResponseToFetchWeatherResponse(t *testing.T) {
 				Name: "London",
 				Sys: struct {
 					Country string `json:"country"`
+					Sunrise int64  `json:"sunrise"`
+					Sunset  int64  `json:"sunset"`
 				}{
 					Country: "GB",
 				},
 				Main: struct {
-					Temp     float64 `json:"temp"`
-					Pressure int     `json:"pressure"`
-					Humidity int     `json:"humidity"`
+					Temp      float64  `json:"temp"`
+					FeelsLike *float64 `json:"feels_like"`
+					TempMin   *float64 `json:"temp_min"`
+					TempMax   *float64 `json:"temp_max"`
+					Pressure  int      `json:"pressure"`
+					Humidity  int      `json:"humidity"`
 				}{
 					Temp:     15.5,
 					Pressure: 1013,
 					Humidity: 75,
 				},
 				Weather: []struct {
+					ID          int    `json:"id"`
 					Main        string `json:"main"`
 					Description string `json:"description"`
 				}{
 					{
+						ID:          802,
 						Main:        "Clouds",
 						Description: "scattered clouds",
 					},
 				},
 				Wind: struct {
-					Speed float64 `json:"speed"`
-					Deg   int     `json:"deg"`
+					Speed float64  `json:"speed"`
+					Deg   *int     `json:"deg"`
+					Gust  *float64 `json:"gust"`
 				}{
 					Speed: 5.2,
-					Deg:   180,
+					Deg:   ptr(180),
 				},
 			},
 			expected: schemata.FetchWeatherResponse{
-				LocationName: "London",
-				Country:      "GB",
-				Temperature:  15.5,
-				Description:  "scattered clouds",
-				Humidity:     75,
-				WindSpeed:    5.2,
+				LocationName:  "London",
+				Country:       "GB",
+				Temperature:   15.5,
+				Description:   "scattered clouds",
+				ConditionCode: ptr(802),
+				Condition:     schemata.ConditionClouds,
+				Intensity:     schemata.IntensityLight,
+				Humidity:      75,
+				WindSpeed:     5.2,
+				Pressure:      ptr(1013),
+				WindDeg:       ptr(180),
 			},
 		},
 		{
@@ -63,28 +79,35 @@ func TestMapOpenWeatherResponseToFetchWeatherResponse(t *testing.T) {
 				Name: "Paris",
 				Sys: struct {
 					Country string `json:"country"`
+					Sunrise int64  `json:"sunrise"`
+					Sunset  int64  `json:"sunset"`
 				}{
 					Country: "FR",
 				},
 				Main: struct {
-					Temp     float64 `json:"temp"`
-					Pressure int     `json:"pressure"`
-					Humidity int     `json:"humidity"`
+					Temp      float64  `json:"temp"`
+					FeelsLike *float64 `json:"feels_like"`
+					TempMin   *float64 `json:"temp_min"`
+					TempMax   *float64 `json:"temp_max"`
+					Pressure  int      `json:"pressure"`
+					Humidity  int      `json:"humidity"`
 				}{
 					Temp:     22.0,
 					Pressure: 1015,
 					Humidity: 60,
 				},
 				Weather: []struct {
+					ID          int    `json:"id"`
 					Main        string `json:"main"`
 					Description string `json:"description"`
 				}{},
 				Wind: struct {
-					Speed float64 `json:"speed"`
-					Deg   int     `json:"deg"`
+					Speed float64  `json:"speed"`
+					Deg   *int     `json:"deg"`
+					Gust  *float64 `json:"gust"`
 				}{
 					Speed: 3.1,
-					Deg:   90,
+					Deg:   ptr(90),
 				},
 			},
 			expected: schemata.FetchWeatherResponse{
@@ -94,6 +117,8 @@ func TestMapOpenWeatherResponseToFetchWeatherResponse(t *testing.T) {
 				Description:  "",
 				Humidity:     60,
 				WindSpeed:    3.1,
+				Pressure:     ptr(1015),
+				WindDeg:      ptr(90),
 			},
 		},
 		{
@@ -102,46 +127,60 @@ func TestMapOpenWeatherResponseToFetchWeatherResponse(t *testing.T) {
 				Name: "New York",
 				Sys: struct {
 					Country string `json:"country"`
+					Sunrise int64  `json:"sunrise"`
+					Sunset  int64  `json:"sunset"`
 				}{
 					Country: "US",
 				},
 				Main: struct {
-					Temp     float64 `json:"temp"`
-					Pressure int     `json:"pressure"`
-					Humidity int     `json:"humidity"`
+					Temp      float64  `json:"temp"`
+					FeelsLike *float64 `json:"feels_like"`
+					TempMin   *float64 `json:"temp_min"`
+					TempMax   *float64 `json:"temp_max"`
+					Pressure  int      `json:"pressure"`
+					Humidity  int      `json:"humidity"`
 				}{
 					Temp:     -5.0,
 					Pressure: 1020,
 					Humidity: 85,
 				},
 				Weather: []struct {
+					ID          int    `json:"id"`
 					Main        string `json:"main"`
 					Description string `json:"description"`
 				}{
 					{
+						ID:          600,
 						Main:        "Snow",
 						Description: "light snow",
 					},
 					{
+						ID:          804,
 						Main:        "Clouds",
 						Description: "overcast clouds",
 					},
 				},
 				Wind: struct {
-					Speed float64 `json:"speed"`
-					Deg   int     `json:"deg"`
+					Speed float64  `json:"speed"`
+					Deg   *int     `json:"deg"`
+					Gust  *float64 `json:"gust"`
 				}{
 					Speed: 8.5,
-					Deg:   270,
+					Deg:   ptr(270),
 				},
 			},
 			expected: schemata.FetchWeatherResponse{
-				LocationName: "New York",
-				Country:      "US",
-				Temperature:  -5.0,
-				Description:  "light snow",
-				Humidity:     85,
-				WindSpeed:    8.5,
+				LocationName:  "New York",
+				Country:       "US",
+				Temperature:   -5.0,
+				Description:   "light snow",
+				ConditionCode: ptr(600),
+				Condition:     schemata.ConditionSnow,
+				Intensity:     schemata.IntensityLight,
+				Humidity:      85,
+				WindSpeed:     8.5,
+				Pressure:      ptr(1020),
+				WindDeg:       ptr(270),
 			},
 		},
 		{
@@ -150,42 +189,53 @@ func TestMapOpenWeatherResponseToFetchWeatherResponse(t *testing.T) {
 				Name: "Tokyo",
 				Sys: struct {
 					Country string `json:"country"`
+					Sunrise int64  `json:"sunrise"`
+					Sunset  int64  `json:"sunset"`
 				}{
 					Country: "JP",
 				},
 				Main: struct {
-					Temp     float64 `json:"temp"`
-					Pressure int     `json:"pressure"`
-					Humidity int     `json:"humidity"`
+					Temp      float64  `json:"temp"`
+					FeelsLike *float64 `json:"feels_like"`
+					TempMin   *float64 `json:"temp_min"`
+					TempMax   *float64 `json:"temp_max"`
+					Pressure  int      `json:"pressure"`
+					Humidity  int      `json:"humidity"`
 				}{
 					Temp:     0.0,
 					Pressure: 0,
 					Humidity: 0,
 				},
 				Weather: []struct {
+					ID          int    `json:"id"`
 					Main        string `json:"main"`
 					Description string `json:"description"`
 				}{
 					{
+						ID:          800,
 						Main:        "Clear",
 						Description: "clear sky",
 					},
 				},
 				Wind: struct {
-					Speed float64 `json:"speed"`
-					Deg   int     `json:"deg"`
+					Speed float64  `json:"speed"`
+					Deg   *int     `json:"deg"`
+					Gust  *float64 `json:"gust"`
 				}{
 					Speed: 0.0,
-					Deg:   0,
+					Deg:   ptr(0),
 				},
 			},
 			expected: schemata.FetchWeatherResponse{
-				LocationName: "Tokyo",
-				Country:      "JP",
-				Temperature:  0.0,
-				Description:  "clear sky",
-				Humidity:     0,
-				WindSpeed:    0.0,
+				LocationName:  "Tokyo",
+				Country:       "JP",
+				Temperature:   0.0,
+				Description:   "clear sky",
+				ConditionCode: ptr(800),
+				Condition:     schemata.ConditionClear,
+				Humidity:      0,
+				WindSpeed:     0.0,
+				WindDeg:       ptr(0),
 			},
 		},
 	}
@@ -196,4 +246,51 @@ func TestMapOpenWeatherResponseToFetchWeatherResponse(t *testing.T) {
 			assert.Equal(t, tt.expected, result)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestMapOpenWeatherResponseToFetchWeatherResponse_Observation(t *testing.T) {
+	payload := `{
+		"name": "London", "dt": 1756728000, "visibility": 10000,
+		"sys": {"country": "GB", "sunrise": 1756703512, "sunset": 1756752301},
+		"main": {"temp": 18.2, "feels_like": 17.9, "temp_min": 16.8, "temp_max": 19.4, "pressure": 1009, "humidity": 72},
+		"wind": {"speed": 4.1, "deg": 230, "gust": 8.2},
+		"clouds": {"all": 75},
+		"rain": {"1h": 0.42}
+	}`
+
+	var owResp Response
+	require.NoError(t, json.Unmarshal([]byte(payload), &owResp))
+
+	result := mapOpenWeatherResponseToFetchWeatherResponse(owResp)
+
+	assert.Equal(t, ptr(1009), result.Pressure)
+	assert.Equal(t, ptr(230), result.WindDeg)
+	assert.Equal(t, ptr(8.2), result.WindGust)
+	assert.Equal(t, ptr(17.9), result.FeelsLike)
+	assert.Equal(t, ptr(16.8), result.TempMin)
+	assert.Equal(t, ptr(19.4), result.TempMax)
+	assert.Equal(t, ptr(10000), result.Visibility)
+	assert.Equal(t, ptr(75), result.Clouds)
+	assert.Equal(t, ptr(0.42), result.Rain1h)
+	assert.Nil(t, result.Rain3h)
+	assert.Nil(t, result.Snow1h)
+	assert.Equal(t, ptr(time.Unix(1756703512, 0).UTC()), result.Sunrise)
+	assert.Equal(t, ptr(time.Unix(1756752301, 0).UTC()), result.Sunset)
+	assert.Equal(t, ptr(time.Unix(1756728000, 0).UTC()), result.ObservedAt)
+}
+
+func TestMapOpenWeatherResponseToFetchWeatherResponse_Unreported(t *testing.T) {
+	payload := `{"name": "London", "sys": {"country": "GB"}, "main": {"temp": 18.2, "humidity": 72}, "wind": {"speed": 0}}`
+
+	var owResp Response
+	require.NoError(t, json.Unmarshal([]byte(payload), &owResp))
+
+	result := mapOpenWeatherResponseToFetchWeatherResponse(owResp)
+
+	assert.Nil(t, result.WindDeg, "calm wind has no direction")
+	assert.Nil(t, result.Coordinates)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
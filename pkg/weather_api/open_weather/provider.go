@@ -0,0 +1,75 @@
+package open_weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/retry"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+const Name = "OpenWeather"
+
+// healthCheckCity is queried by HealthCheck; any well-known city works.
+const healthCheckCity = "London"
+
+type Provider struct {
+	config    conf.Config
+	transport *retry.Transport
+}
+
+func NewProvider(config conf.Config) Provider {
+	return Provider{
+		config:    config,
+		transport: &retry.Transport{Base: http.DefaultTransport, Config: config.Retry},
+	}
+}
+
+func (p Provider) Name() string {
+	return Name
+}
+
+func (p Provider) MapCurrent(raw json.RawMessage) (*schemata.FetchWeatherResponse, error) {
+	return MapWeather(raw)
+}
+
+func (p Provider) HealthCheck(ctx context.Context) error {
+	_, err := p.FetchCurrent(ctx, schemata.Location{CityName: healthCheckCity})
+
+	return err
+}
+
+func (p Provider) RateLimit() conf.RateLimitConfig {
+	return p.config.OpenWeather.RateLimit
+}
+
+func (p Provider) APIKey() string {
+	return p.config.OpenWeather.ApiKey
+}
+
+// Limit makes every request the provider sends, retries included, wait for acquire first.
+func (p Provider) Limit(acquire func(ctx context.Context) error) {
+	p.transport.Acquire = acquire
+}
+
+// client returns the http.Client sending the provider's requests through its transport.
+func (p Provider) client() *http.Client {
+	return &http.Client{Transport: p.transport}
+}
+
+func (p Provider) IsTransient(err error) bool {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, UnhandledError), errors.Is(err, RateLimitedErr):
+		return true
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,161 @@
+package open_weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider_Metadata(t *testing.T) {
+	provider := NewProvider(conf.Config{})
+
+	assert.Equal(t, "OpenWeather", provider.Name())
+}
+
+func TestProvider_FetchCurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Paris,FR", r.URL.Query().Get("q"))
+		assert.Equal(t, "test-api-key", r.URL.Query().Get("appid"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"Paris","sys":{"country":"FR"},"main":{"temp":20,"humidity":70}}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := GetBaseURL()
+	SetBaseURL(server.URL)
+	defer SetBaseURL(originalBaseURL)
+
+	config := conf.Config{}
+	config.OpenWeather.ApiKey = "test-api-key"
+
+	result, err := NewProvider(config).FetchCurrent(context.Background(), schemata.Location{CityName: "Paris", Country: "FR"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Paris", result.LocationName)
+	assert.Equal(t, 20.0, result.Temperature)
+}
+
+func TestProvider_FetchCurrent_Coordinates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("q"))
+		assert.Equal(t, "37.2153", r.URL.Query().Get("lat"))
+		assert.Equal(t, "-93.2982", r.URL.Query().Get("lon"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"Springfield","coord":{"lat":37.2153,"lon":-93.2982},"sys":{"country":"US"},"main":{"temp":24,"humidity":60}}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := GetBaseURL()
+	SetBaseURL(server.URL)
+	defer SetBaseURL(originalBaseURL)
+
+	location := schemata.Location{CityName: "Springfield", Coordinates: &schemata.Coordinates{Latitude: 37.2153, Longitude: -93.2982}}
+	result, err := NewProvider(conf.Config{}).FetchCurrent(context.Background(), location)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Springfield", result.LocationName)
+	assert.Equal(t, &schemata.Coordinates{Latitude: 37.2153, Longitude: -93.2982}, result.Coordinates)
+}
+
+func TestProvider_FetchCurrent_Language(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "de", r.URL.Query().Get("lang"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"Berlin","sys":{"country":"DE"},"main":{"temp":8,"humidity":90},"weather":[{"id":500,"description":"leichter Regen"}]}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := GetBaseURL()
+	SetBaseURL(server.URL)
+	defer SetBaseURL(originalBaseURL)
+
+	result, err := NewProvider(conf.Config{}).FetchCurrent(context.Background(), schemata.Location{CityName: "Berlin", Language: "de"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "leichter Regen", result.Description)
+	assert.Equal(t, 500, *result.ConditionCode)
+}
+
+func TestProvider_MapCurrent(t *testing.T) {
+	raw := json.RawMessage(`{"name":"Paris","sys":{"country":"FR"},"main":{"temp":20,"humidity":70,"pressure":1012}}`)
+
+	result, err := NewProvider(conf.Config{}).MapCurrent(raw)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Paris", result.LocationName)
+	assert.Equal(t, 20.0, result.Temperature)
+	assert.Equal(t, 1012, *result.Pressure)
+	assert.Equal(t, raw, result.Raw)
+
+	_, err = NewProvider(conf.Config{}).MapCurrent(json.RawMessage(`not json`))
+	assert.Error(t, err)
+}
+
+func TestProvider_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		expectedError error
+	}{
+		{
+			name:          "healthy",
+			statusCode:    http.StatusOK,
+			expectedError: nil,
+		},
+		{
+			name:          "unhealthy",
+			statusCode:    http.StatusInternalServerError,
+			expectedError: UnhandledError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			originalBaseURL := GetBaseURL()
+			SetBaseURL(server.URL)
+			defer SetBaseURL(originalBaseURL)
+
+			err := NewProvider(conf.Config{}).HealthCheck(context.Background())
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestProvider_IsTransient(t *testing.T) {
+	provider := NewProvider(conf.Config{})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "unhandled error", err: UnhandledError, expected: true},
+		{name: "rate limited", err: RateLimitedErr, expected: true},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expected: true},
+		{name: "not found", err: NotFoundErr, expected: false},
+		{name: "decode error", err: errors.New("invalid character"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, provider.IsTransient(tt.err))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package open_weather
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
+)
+
+// severityWords grades One Call alerts, which come ungraded, by the words agencies name their events with:
+// the MeteoAlarm colors of european agencies ("Orange wind warning") and the advisory, watch and warning
+// levels of the US National Weather Service ("Heat Advisory"). An event is graded by the first word found,
+// colors before levels since european agencies call warnings of every color warnings.
+var severityWords = []struct {
+	severity schemata.Severity
+	words    []string
+}{
+	{schemata.SeverityExtreme, []string{"red"}},
+	{schemata.SeveritySevere, []string{"orange"}},
+	{schemata.SeverityModerate, []string{"yellow"}},
+	{schemata.SeverityMinor, []string{"green"}},
+	{schemata.SeverityExtreme, []string{"extreme", "emergency"}},
+	{schemata.SeveritySevere, []string{"severe", "warning"}},
+	{schemata.SeverityModerate, []string{"watch"}},
+	{schemata.SeverityMinor, []string{"advisory", "statement"}},
+}
+
+// eventSeverities grades the US National Weather Service events named with a MeteoAlarm color word,
+// which aren't graded by their color.
+var eventSeverities = map[string]schemata.Severity{
+	"red flag warning": schemata.SeveritySevere,
+}
+
+// severityOf grades an alert by its event, unknown when no word of the event tells.
+func severityOf(event string) schemata.Severity {
+	if severity, ok := eventSeverities[strings.ToLower(strings.TrimSpace(event))]; ok {
+		return severity
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(event), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	for _, grade := range severityWords {
+		for _, word := range words {
+			for _, severityWord := range grade.words {
+				if word == severityWord {
+					return grade.severity
+				}
+			}
+		}
+	}
+
+	return schemata.SeverityUnknown
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
@@ -15,8 +16,14 @@ import (
 
 var baseURL = "https://api.openweathermap.org/data/2.5/weather"
 
+// units is the unit system answers are asked in: metric, with temperatures in Celsius, like every provider answer.
+// Conversion to other systems is left to the readers of the answers.
+const units = "metric"
+
 var (
-	NotFoundErr    = errors.New("not-found")
+	// NotFoundErr wraps schemata.NotFoundErr
+	NotFoundErr    = fmt.Errorf("%w", schemata.NotFoundErr)
+	RateLimitedErr = errors.New("rate-limited")
 	UnhandledError = errors.New("unhandled-error")
 )
 
@@ -30,27 +37,78 @@ func GetBaseURL() string {
 	return baseURL
 }
 
-func FetchWeatherByLocation(ctx context.Context, cityName, country string, config conf.Config) (*schemata.FetchWeatherResponse, error) {
-	if country != "" {
-		country = "," + country
+// FetchWeatherByLocation fetches the current weather of a location through a provider built from config.
+func FetchWeatherByLocation(ctx context.Context, location schemata.Location, config conf.Config) (*schemata.FetchWeatherResponse, error) {
+	return NewProvider(config).FetchCurrent(ctx, location)
+}
+
+func (p Provider) FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error) {
+	var raw json.RawMessage
+	if err := p.get(ctx, GetBaseURL(), locationQuery(location), &raw); err != nil {
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s?q=%s%s&appid=%s&units=metric", GetBaseURL(), cityName, country, config.OpenWeather.ApiKey)
+	return MapWeather(raw)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
+// MapWeather maps raw, a body answered by the current weather endpoint.
+func MapWeather(raw json.RawMessage) (*schemata.FetchWeatherResponse, error) {
+	var owResp Response
+	if err := json.Unmarshal(raw, &owResp); err != nil {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	dto := mapOpenWeatherResponseToFetchWeatherResponse(owResp)
+	dto.Raw = raw
+
+	return &dto, nil
+}
+
+func locationQuery(location schemata.Location) url.Values {
+	query := url.Values{}
+
+	if location.Coordinates != nil {
+		query.Set("lat", fmt.Sprint(location.Coordinates.Latitude))
+		query.Set("lon", fmt.Sprint(location.Coordinates.Longitude))
+	} else {
+		q := location.CityName
+		if location.Country != "" {
+			q += "," + location.Country
+		}
+
+		query.Set("q", q)
+	}
+
+	if location.Language != "" {
+		query.Set("lang", location.Language)
+	}
+
+	return query
+}
+
+// get queries endpoint with query, in units, and decodes the answer into out.
+func (p Provider) get(ctx context.Context, endpoint string, query url.Values, out any) error {
+	params := url.Values{}
+	for key, values := range query {
+		params[key] = values
+	}
+	params.Set("appid", p.config.OpenWeather.ApiKey)
+	params.Set("units", units)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return err
 	}
 
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
-			log.Println("error in closing body in open_weather.FetchWeatherByLocation")
+			log.Println("error in closing body in open_weather.get")
 		}
 	}(resp.Body)
 
@@ -58,20 +116,16 @@ func FetchWeatherByLocation(ctx context.Context, cityName, country string, confi
 		switch resp.StatusCode {
 		case http.StatusNotFound:
 			err = NotFoundErr
+		case http.StatusTooManyRequests:
+			err = RateLimitedErr
 		default:
-			log.Printf("error in open_weather.FetchWeatherByLocation %v : %v - %v", resp.StatusCode, cityName, country)
+			// query is logged rather than the url, which holds the api key
+			log.Printf("error in open_weather.get %v : %v - %v", resp.StatusCode, endpoint, query.Encode())
 			err = UnhandledError
 		}
 
-		return nil, err
-	}
-
-	var owResp Response
-	if err := json.NewDecoder(resp.Body).Decode(&owResp); err != nil {
-		return nil, err
+		return err
 	}
 
-	dto := mapOpenWeatherResponseToFetchWeatherResponse(owResp)
-
-	return &dto, nil
+	return json.NewDecoder(resp.Body).Decode(out)
 }
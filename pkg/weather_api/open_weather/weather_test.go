@@ -33,19 +33,25 @@ func TestFetchWeatherByLocation(t *testing.T) {
 				Name: "London",
 				Sys: struct {
 					Country string `json:"country"`
+					Sunrise int64  `json:"sunrise"`
+					Sunset  int64  `json:"sunset"`
 				}{
 					Country: "UK",
 				},
 				Main: struct {
-					Temp     float64 `json:"temp"`
-					Pressure int     `json:"pressure"`
-					Humidity int     `json:"humidity"`
+					Temp      float64  `json:"temp"`
+					FeelsLike *float64 `json:"feels_like"`
+					TempMin   *float64 `json:"temp_min"`
+					TempMax   *float64 `json:"temp_max"`
+					Pressure  int      `json:"pressure"`
+					Humidity  int      `json:"humidity"`
 				}{
 					Temp:     15.5,
 					Pressure: 1013,
 					Humidity: 65,
 				},
 				Weather: []struct {
+					ID          int    `json:"id"`
 					Main        string `json:"main"`
 					Description string `json:"description"`
 				}{
@@ -55,11 +61,12 @@ func TestFetchWeatherByLocation(t *testing.T) {
 					},
 				},
 				Wind: struct {
-					Speed float64 `json:"speed"`
-					Deg   int     `json:"deg"`
+					Speed float64  `json:"speed"`
+					Deg   *int     `json:"deg"`
+					Gust  *float64 `json:"gust"`
 				}{
 					Speed: 5.2,
-					Deg:   180,
+					Deg:   ptr(180),
 				},
 			},
 			mockStatusCode: http.StatusOK,
@@ -93,6 +100,16 @@ func TestFetchWeatherByLocation(t *testing.T) {
 			expectedResult: nil,
 			expectedError:  UnhandledError,
 		},
+		{
+			name:           "rate limited",
+			cityName:       "London",
+			country:        "UK",
+			apiKey:         "test-api-key",
+			mockResponse:   Response{},
+			mockStatusCode: http.StatusTooManyRequests,
+			expectedResult: nil,
+			expectedError:  RateLimitedErr,
+		},
 		{
 			name:     "empty country parameter",
 			cityName: "Paris",
@@ -102,19 +119,25 @@ func TestFetchWeatherByLocation(t *testing.T) {
 				Name: "Paris",
 				Sys: struct {
 					Country string `json:"country"`
+					Sunrise int64  `json:"sunrise"`
+					Sunset  int64  `json:"sunset"`
 				}{
 					Country: "FR",
 				},
 				Main: struct {
-					Temp     float64 `json:"temp"`
-					Pressure int     `json:"pressure"`
-					Humidity int     `json:"humidity"`
+					Temp      float64  `json:"temp"`
+					FeelsLike *float64 `json:"feels_like"`
+					TempMin   *float64 `json:"temp_min"`
+					TempMax   *float64 `json:"temp_max"`
+					Pressure  int      `json:"pressure"`
+					Humidity  int      `json:"humidity"`
 				}{
 					Temp:     20.0,
 					Pressure: 1015,
 					Humidity: 70,
 				},
 				Weather: []struct {
+					ID          int    `json:"id"`
 					Main        string `json:"main"`
 					Description string `json:"description"`
 				}{
@@ -124,11 +147,12 @@ func TestFetchWeatherByLocation(t *testing.T) {
 					},
 				},
 				Wind: struct {
-					Speed float64 `json:"speed"`
-					Deg   int     `json:"deg"`
+					Speed float64  `json:"speed"`
+					Deg   *int     `json:"deg"`
+					Gust  *float64 `json:"gust"`
 				}{
 					Speed: 3.1,
-					Deg:   90,
+					Deg:   ptr(90),
 				},
 			},
 			mockStatusCode: http.StatusOK,
@@ -180,7 +204,7 @@ func TestFetchWeatherByLocation(t *testing.T) {
 			config.OpenWeather.ApiKey = tt.apiKey
 
 			// Call the function
-			result, err := FetchWeatherByLocation(context.Background(), tt.cityName, tt.country, config)
+			result, err := FetchWeatherByLocation(context.Background(), schemata.Location{CityName: tt.cityName, Country: tt.country}, config)
 
 			// Assertions
 			if tt.expectedError != nil {
@@ -220,7 +244,7 @@ func TestFetchWeatherByLocation_InvalidJSON(t *testing.T) {
 	config.OpenWeather.ApiKey = "test-api-key"
 
 	// Call the function
-	result, err := FetchWeatherByLocation(context.Background(), "London", "UK", config)
+	result, err := FetchWeatherByLocation(context.Background(), schemata.Location{CityName: "London", Country: "UK"}, config)
 
 	// Should return error due to invalid JSON
 	assert.Error(t, err)
@@ -238,7 +262,7 @@ func TestFetchWeatherByLocation_NetworkError(t *testing.T) {
 	config.OpenWeather.ApiKey = "test-api-key"
 
 	// Call the function
-	result, err := FetchWeatherByLocation(context.Background(), "London", "UK", config)
+	result, err := FetchWeatherByLocation(context.Background(), schemata.Location{CityName: "London", Country: "UK"}, config)
 
 	// Should return error due to network failure
 	assert.Error(t, err)
@@ -257,3 +281,30 @@ func TestSetBaseURL(t *testing.T) {
 	SetBaseURL(originalURL)
 	assert.Equal(t, originalURL, GetBaseURL())
 }
+
+func TestFetchWeatherByLocation_Retry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.Write([]byte(`{"name":"London","sys":{"country":"GB"},"main":{"temp":15.5,"humidity":65}}`))
+	}))
+	defer server.Close()
+
+	originalBaseURL := GetBaseURL()
+	SetBaseURL(server.URL)
+	defer SetBaseURL(originalBaseURL)
+
+	config := conf.Config{}
+	config.Retry.MaxAttempts = 2
+
+	result, err := FetchWeatherByLocation(context.Background(), schemata.Location{CityName: "London", Country: "GB"}, config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "London", result.LocationName)
+}
@@ -0,0 +1,168 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+)
+
+// Transport is an http.RoundTripper retrying idempotent requests on network errors,
+// 5xx and 429 responses with exponential backoff and full jitter. A Retry-After header
+// on the response takes precedence over the computed backoff. Retries never outlive
+// the request context: when the next wait would pass its deadline the last outcome is returned.
+type Transport struct {
+	Base   http.RoundTripper
+	Config conf.RetryConfig
+	// Acquire, when set, is called before every attempt so that each request sent is accounted for. The request
+	// fails with its error, without being sent or retried, when it returns one.
+	Acquire func(ctx context.Context) error
+
+	// sleep waits for d or until ctx is done; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient returns an http.Client retrying according to config on top of http.DefaultTransport.
+func NewClient(config conf.RetryConfig) *http.Client {
+	return &http.Client{
+		Transport: &Transport{
+			Base:   http.DefaultTransport,
+			Config: config,
+		},
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if !isIdempotent(req.Method) || t.Config.MaxAttempts <= 1 {
+		if err := t.acquire(ctx); err != nil {
+			return nil, err
+		}
+
+		return t.base().RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := t.acquire(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base().RoundTrip(req)
+		if attempt >= t.Config.MaxAttempts || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+
+		if resp != nil {
+			drain(resp.Body)
+		}
+
+		if err := t.wait(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Transport) acquire(ctx context.Context) error {
+	if t.Acquire == nil {
+		return nil
+	}
+
+	return t.Acquire(ctx)
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+
+	return t.Base
+}
+
+// backoff returns a random duration up to InitialBackoff * 2^(attempt-1), capped at MaxBackoff.
+func (t *Transport) backoff(attempt int) time.Duration {
+	ceiling := t.Config.InitialBackoff << (attempt - 1)
+	if ceiling <= 0 || (t.Config.MaxBackoff > 0 && ceiling > t.Config.MaxBackoff) {
+		ceiling = t.Config.MaxBackoff
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return rand.N(ceiling + 1)
+}
+
+func (t *Transport) wait(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter reads a Retry-After header holding either delay seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 4096))
+	_ = body.Close()
+}
@@ -0,0 +1,248 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = conf.RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     time.Second,
+}
+
+// newTestClient returns a client whose waits are recorded instead of slept.
+func newTestClient(config conf.RetryConfig) (*http.Client, *[]time.Duration) {
+	var waits []time.Duration
+
+	transport := &Transport{
+		Base:   http.DefaultTransport,
+		Config: config,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	}
+
+	return &http.Client{Transport: transport}, &waits
+}
+
+// newStatusServer responds with statuses in order, repeating the last one.
+func newStatusServer(t *testing.T, attempts *int32, headers http.Header, statuses ...int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(atomic.AddInt32(attempts, 1))
+		status := statuses[min(attempt, len(statuses))-1]
+
+		for key, values := range headers {
+			w.Header()[key] = values
+		}
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		statuses         []int
+		expectedStatus   int
+		expectedAttempts int32
+	}{
+		{
+			name:             "success on first attempt",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusOK},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "retries server errors until success",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusBadGateway, http.StatusInternalServerError, http.StatusOK},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "retries rate limiting",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "gives up after max attempts",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusServiceUnavailable},
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "does not retry client errors",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusNotFound},
+			expectedStatus:   http.StatusNotFound,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "does not retry non idempotent methods",
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusInternalServerError},
+			expectedStatus:   http.StatusInternalServerError,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := newStatusServer(t, &attempts, nil, tt.statuses...)
+			client, waits := newTestClient(testConfig)
+
+			req, err := http.NewRequest(tt.method, server.URL, nil)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+			assert.Len(t, *waits, int(tt.expectedAttempts)-1)
+
+			for i, wait := range *waits {
+				assert.LessOrEqual(t, wait, testConfig.InitialBackoff<<i)
+			}
+		})
+	}
+}
+
+func TestTransport_RetryAfter(t *testing.T) {
+	var attempts int32
+	server := newStatusServer(t, &attempts, http.Header{"Retry-After": []string{"7"}}, http.StatusTooManyRequests, http.StatusOK)
+	client, waits := newTestClient(testConfig)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{7 * time.Second}, *waits)
+}
+
+func TestTransport_ContextDeadline(t *testing.T) {
+	var attempts int32
+	server := newStatusServer(t, &attempts, http.Header{"Retry-After": []string{"30"}}, http.StatusServiceUnavailable)
+	client, waits := newTestClient(testConfig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// waiting 30s would outlive the request context, so the first response is returned
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	assert.Empty(t, *waits)
+}
+
+func TestTransport_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client, waits := newTestClient(testConfig)
+
+	_, err := client.Get(url)
+
+	assert.Error(t, err)
+	assert.Len(t, *waits, testConfig.MaxAttempts-1)
+}
+
+func TestTransport_Acquire(t *testing.T) {
+	var attempts int32
+	server := newStatusServer(t, &attempts, nil, http.StatusServiceUnavailable, http.StatusOK)
+
+	t.Run("every attempt is acquired", func(t *testing.T) {
+		client, _ := newTestClient(testConfig)
+		acquired := 0
+		client.Transport.(*Transport).Acquire = func(ctx context.Context) error {
+			acquired++
+			return nil
+		}
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, acquired)
+	})
+
+	t.Run("a refused attempt is neither sent nor retried", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+		limitErr := errors.New("limited")
+
+		client, waits := newTestClient(testConfig)
+		client.Transport.(*Transport).Acquire = func(ctx context.Context) error {
+			return limitErr
+		}
+
+		_, err := client.Get(server.URL)
+
+		assert.ErrorIs(t, err, limitErr)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&attempts))
+		assert.Empty(t, *waits)
+	})
+}
+
+func TestTransport_Disabled(t *testing.T) {
+	var attempts int32
+	server := newStatusServer(t, &attempts, nil, http.StatusInternalServerError)
+
+	resp, err := NewClient(conf.RetryConfig{}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "empty", value: "", ok: false},
+		{name: "seconds", value: "3", expected: 3 * time.Second, ok: true},
+		{name: "date in the past", value: "Mon, 02 Jan 2006 15:04:05 GMT", expected: 0, ok: true},
+		{name: "garbage", value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := parseRetryAfter(tt.value)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
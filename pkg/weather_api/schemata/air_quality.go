@@ -0,0 +1,26 @@
+package schemata
+
+import "time"
+
+// FetchAirQualityResponse is the air quality at a location. Concentrations are in μg/m3 and nil when the
+// provider doesn't report them.
+type FetchAirQualityResponse struct {
+	// Provider is the name of the provider that served the response
+	Provider     string
+	LocationName string
+	Country      string
+	Latitude     float64
+	Longitude    float64
+	// AQI is the air quality index, from 1 (good) to 5 (very poor)
+	AQI  int
+	CO   *float64
+	NO   *float64
+	NO2  *float64
+	O3   *float64
+	SO2  *float64
+	PM25 *float64
+	PM10 *float64
+	NH3  *float64
+	// ObservedAt is when the provider observed the air quality
+	ObservedAt *time.Time
+}
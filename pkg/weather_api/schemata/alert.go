@@ -0,0 +1,41 @@
+package schemata
+
+import "time"
+
+// Severity grades an alert, after the CAP (Common Alerting Protocol) severities.
+type Severity string
+
+const (
+	SeverityMinor    Severity = "minor"
+	SeverityModerate Severity = "moderate"
+	SeveritySevere   Severity = "severe"
+	SeverityExtreme  Severity = "extreme"
+	// SeverityUnknown is the severity of alerts the provider doesn't grade in a way that can be told
+	SeverityUnknown Severity = "unknown"
+)
+
+// Severities lists the severities, from the least to the most severe.
+var Severities = []Severity{SeverityMinor, SeverityModerate, SeveritySevere, SeverityExtreme, SeverityUnknown}
+
+// Alert is an official weather warning issued for a location.
+type Alert struct {
+	// Sender is the agency that issued the alert
+	Sender      string
+	Event       string
+	Severity    Severity
+	Start       time.Time
+	End         time.Time
+	Description string
+	Tags        []string
+}
+
+// FetchAlertsResponse holds the alerts in effect or upcoming at a location, none when there are none.
+type FetchAlertsResponse struct {
+	// Provider is the name of the provider that served the response
+	Provider     string
+	LocationName string
+	Country      string
+	Latitude     float64
+	Longitude    float64
+	Alerts       []Alert
+}
@@ -0,0 +1,13 @@
+package schemata
+
+type Capability string
+
+const (
+	CurrentWeatherCapability   Capability = "current-weather"
+	ForecastCapability         Capability = "forecast"
+	GeocodingCapability        Capability = "geocoding"
+	ReverseGeocodingCapability Capability = "reverse-geocoding"
+	HistoryCapability          Capability = "history"
+	AirQualityCapability       Capability = "air-quality"
+	AlertsCapability           Capability = "alerts"
+)
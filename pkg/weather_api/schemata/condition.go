@@ -0,0 +1,48 @@
+package schemata
+
+// Condition is the kind of weather observed, whatever the provider describing it. It is empty when the
+// provider's code is unknown.
+type Condition string
+
+const (
+	ConditionClear        Condition = "clear"
+	ConditionClouds       Condition = "clouds"
+	ConditionDrizzle      Condition = "drizzle"
+	ConditionRain         Condition = "rain"
+	ConditionSleet        Condition = "sleet"
+	ConditionSnow         Condition = "snow"
+	ConditionThunderstorm Condition = "thunderstorm"
+	// ConditionFog covers every haze lowering visibility: mist, fog, haze and smoke
+	ConditionFog     Condition = "fog"
+	ConditionDust    Condition = "dust"
+	ConditionSquall  Condition = "squall"
+	ConditionTornado Condition = "tornado"
+)
+
+// Conditions lists every Condition.
+var Conditions = []Condition{
+	ConditionClear,
+	ConditionClouds,
+	ConditionDrizzle,
+	ConditionRain,
+	ConditionSleet,
+	ConditionSnow,
+	ConditionThunderstorm,
+	ConditionFog,
+	ConditionDust,
+	ConditionSquall,
+	ConditionTornado,
+}
+
+// Intensity grades a Condition: how much it rains or snows, how cloudy it is. It is empty for conditions
+// that aren't graded, like clear sky, or when the provider doesn't grade them.
+type Intensity string
+
+const (
+	IntensityLight    Intensity = "light"
+	IntensityModerate Intensity = "moderate"
+	IntensityHeavy    Intensity = "heavy"
+)
+
+// Intensities lists every Intensity, from the lightest.
+var Intensities = []Intensity{IntensityLight, IntensityModerate, IntensityHeavy}
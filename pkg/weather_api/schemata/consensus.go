@@ -0,0 +1,10 @@
+package schemata
+
+// ConsensusResponse is the merge of the readings of several providers for the same location.
+type ConsensusResponse struct {
+	FetchWeatherResponse
+	// Readings are the per-provider responses the consensus was computed from
+	Readings []FetchWeatherResponse
+	// Disagreements lists the fields whose readings diverged beyond the configured thresholds
+	Disagreements []string
+}
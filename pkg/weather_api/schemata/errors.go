@@ -0,0 +1,7 @@
+package schemata
+
+import "errors"
+
+// NotFoundErr is wrapped by the errors providers answer when they know no such place, so that callers
+// tell them apart whatever the provider.
+var NotFoundErr = errors.New("not-found")
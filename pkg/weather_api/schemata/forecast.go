@@ -0,0 +1,23 @@
+package schemata
+
+import "time"
+
+// FetchForecastResponse is the forecast of a location as a time series ordered by time.
+type FetchForecastResponse struct {
+	// Provider is the name of the provider that served the response
+	Provider     string
+	LocationName string
+	Country      string
+	Entries      []ForecastEntry
+}
+
+// ForecastEntry is the weather forecast for the period starting at Time.
+type ForecastEntry struct {
+	Time        time.Time
+	Temperature float64
+	Description string
+	Humidity    int
+	WindSpeed   float64
+	// ConditionCode is the provider's code of the condition Description describes, nil when it has none
+	ConditionCode *int
+}
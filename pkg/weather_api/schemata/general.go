@@ -1,10 +1,56 @@
 package schemata
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// FetchWeatherResponse is in metric units: temperatures in Celsius and speeds in meters per second.
 type FetchWeatherResponse struct {
+	// Provider is the name of the provider that served the response
+	Provider     string
 	LocationName string
 	Country      string
-	Temperature  float64
-	Description  string
-	Humidity     int
-	WindSpeed    float64
+	// Coordinates are where the provider located the weather, nil when it doesn't report them
+	Coordinates *Coordinates
+	Temperature float64
+	Description string
+	// ConditionCode is the provider's code of the condition Description describes, nil on merged responses
+	// since codes of different providers don't compare
+	ConditionCode *int
+	// Condition and Intensity classify the weather whatever the provider, empty when its code is unknown
+	Condition Condition
+	Intensity Intensity
+	Humidity  int
+	WindSpeed float64
+
+	// The fields below are nil when the provider doesn't report them
+
+	// Pressure is the atmospheric pressure at sea level in hPa
+	Pressure *int
+	// WindDeg is the direction the wind blows from in degrees, 0 being north
+	WindDeg  *int
+	WindGust *float64
+	// FeelsLike, TempMin and TempMax are temperatures like Temperature. TempMin and TempMax bound the
+	// temperatures observed across the area at the time, not over the day
+	FeelsLike *float64
+	TempMin   *float64
+	TempMax   *float64
+	// Visibility is in meters
+	Visibility *int
+	// Clouds is the cloudiness in percent
+	Clouds *int
+	// Rain1h, Rain3h, Snow1h and Snow3h are the volumes fallen over the last hour and 3 hours in mm
+	Rain1h  *float64
+	Rain3h  *float64
+	Snow1h  *float64
+	Snow3h  *float64
+	Sunrise *time.Time
+	Sunset  *time.Time
+	// ObservedAt is when the provider observed the weather, which may be well before it was fetched
+	ObservedAt *time.Time
+
+	// Raw is the response body the provider answered, mapped again by weather_api.Mapper implementations.
+	// It is nil on merged responses
+	Raw json.RawMessage
 }
@@ -0,0 +1,20 @@
+package schemata
+
+// GeocodingResponse holds the places matching a geocoding query, best match first.
+type GeocodingResponse struct {
+	// Provider is the name of the provider that served the response
+	Provider string
+	Places   []Place
+}
+
+// Place is a named location known to a provider's geocoding API.
+type Place struct {
+	Name string
+	// Country is the ISO 3166-1 alpha-2 country code
+	Country   string
+	State     string
+	Latitude  float64
+	Longitude float64
+	// Timezone is the IANA time zone of the place, empty when the provider doesn't know it
+	Timezone string
+}
@@ -0,0 +1,12 @@
+package schemata
+
+// FetchHistoryResponse holds the hourly observations of a location over a past period.
+type FetchHistoryResponse struct {
+	// Provider is the name of the provider that served the response
+	Provider     string
+	LocationName string
+	Country      string
+	// Observations are ordered by time, each with its ObservedAt set. Hours the provider has no
+	// observation of yet are left out. Their Raw is nil: the provider answers every hour in one body
+	Observations []FetchWeatherResponse
+}
@@ -0,0 +1,23 @@
+package schemata
+
+import "fmt"
+
+// Location is where weather is asked for: a city, optionally narrowed down to a country,
+// or a pair of coordinates, which win over the city when both are set.
+type Location struct {
+	CityName    string
+	Country     string
+	Coordinates *Coordinates
+	// Language is the ISO 639-1 code of the language descriptions are asked in, the provider's default when empty.
+	// Providers unable to describe weather in another language answer in English
+	Language string
+}
+
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (c Coordinates) String() string {
+	return fmt.Sprintf("%v,%v", c.Latitude, c.Longitude)
+}
@@ -0,0 +1,20 @@
+package schemata
+
+// The responses of capabilities served by the first capable provider of a chain are told the name of
+// the provider that served them through SetProvider.
+
+func (r *FetchForecastResponse) SetProvider(name string) {
+	r.Provider = name
+}
+
+func (r *FetchAirQualityResponse) SetProvider(name string) {
+	r.Provider = name
+}
+
+func (r *FetchAlertsResponse) SetProvider(name string) {
+	r.Provider = name
+}
+
+func (r *FetchHistoryResponse) SetProvider(name string) {
+	r.Provider = name
+}
@@ -3,7 +3,11 @@ package weather_api
 import (
 	"context"
 	"errors"
+	"sort"
+	"sync"
+
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_meteo"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_weather"
 	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 )
@@ -11,20 +15,149 @@ import (
 type WeatherProvider string
 
 const (
-	OpenWeather WeatherProvider = "OpenWeather"
+	OpenWeather WeatherProvider = open_weather.Name
+	OpenMeteo   WeatherProvider = open_meteo.Name
 )
 
-var FetchWeatherByLocationFunc = map[WeatherProvider]FetchWeatherByLocation{
-	OpenWeather: open_weather.FetchWeatherByLocation,
+var ProviderNotImplementedErr = errors.New("invalid weather provider name | provider not implemented")
+
+// Provider is the contract every weather data source implements. Name must be
+// unique across a Registry since it is used as the lookup key. Providers with more capabilities
+// than fetching the current weather implement the interfaces of those, such as Forecaster.
+type Provider interface {
+	Name() string
+	FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error)
+	// HealthCheck reports whether the provider answers, checked on demand by the provider statuses
+	HealthCheck(ctx context.Context) error
+	// IsTransient reports whether err, returned by one of the provider's calls, is a failure
+	// another provider may not share (network errors, 5xx, 429) rather than a definitive answer.
+	IsTransient(err error) bool
+}
+
+type Registry struct {
+	mu            sync.RWMutex
+	providers     map[WeatherProvider]Provider
+	breakers      map[WeatherProvider]*CircuitBreaker
+	limiters      map[WeatherProvider]*Limiter
+	breakerConfig conf.CircuitBreakerConfig
+	quotaStore    QuotaStore
+}
+
+// NewRegistry returns an empty Registry whose providers get a circuit breaker configured
+// with breakerConfig. Calls to Limited providers are accounted in quotaStore, kept in
+// memory when nil.
+func NewRegistry(breakerConfig conf.CircuitBreakerConfig, quotaStore QuotaStore) *Registry {
+	if quotaStore == nil {
+		quotaStore = NewMemoryQuotaStore()
+	}
+
+	return &Registry{
+		providers:     make(map[WeatherProvider]Provider),
+		breakers:      make(map[WeatherProvider]*CircuitBreaker),
+		limiters:      make(map[WeatherProvider]*Limiter),
+		breakerConfig: breakerConfig,
+		quotaStore:    quotaStore,
+	}
 }
 
-type FetchWeatherByLocation func(ctx context.Context, cityName, country string, config conf.Config) (*schemata.FetchWeatherResponse, error)
+// LoadRegistry returns a Registry holding every built-in provider configured with config.
+func LoadRegistry(config conf.Config, quotaStore QuotaStore) *Registry {
+	registry := NewRegistry(config.CircuitBreaker, quotaStore)
+	registry.Register(open_weather.NewProvider(config))
+	registry.Register(open_meteo.NewProvider(config))
 
-func LoadFetchWeatherByLocationFunc(provider WeatherProvider) (FetchWeatherByLocation, error) {
-	fetchWeather, ok := FetchWeatherByLocationFunc[provider]
+	return registry
+}
+
+// Register adds provider to the registry, replacing any provider registered under the
+// same name. The provider starts with a closed circuit and, if it is Limited, a full rate limit.
+func (r *Registry) Register(provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := WeatherProvider(provider.Name())
+	r.providers[name] = provider
+	r.breakers[name] = NewCircuitBreaker(provider.Name(), r.breakerConfig)
+
+	delete(r.limiters, name)
+	if limited, ok := provider.(Limited); ok {
+		limiter := NewLimiter(provider.Name(), limited.APIKey(), limited.RateLimit(), r.quotaStore)
+		limited.Limit(limiter.Acquire)
+		r.limiters[name] = limiter
+	}
+}
+
+func (r *Registry) Get(name WeatherProvider) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, ok := r.providers[name]
 	if !ok {
-		return nil, errors.New("invalid weather provider name | provider not implemented")
+		return nil, ProviderNotImplementedErr
+	}
+
+	return provider, nil
+}
+
+// Breaker returns the circuit breaker guarding the provider registered under name, nil if there is none.
+func (r *Registry) Breaker(name WeatherProvider) *CircuitBreaker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.breakers[name]
+}
+
+// Limiter returns the rate limiter of the provider registered under name, nil if the provider isn't Limited.
+func (r *Registry) Limiter(name WeatherProvider) *Limiter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.limiters[name]
+}
+
+// Names returns the registered provider names in a stable order.
+func (r *Registry) Names() []WeatherProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]WeatherProvider, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	return names
+}
+
+// optionalCapabilities are the capabilities a provider has by implementing their interface, in the
+// order Capabilities lists them.
+var optionalCapabilities = []struct {
+	capability  schemata.Capability
+	implemented func(provider Provider) bool
+}{
+	{schemata.ForecastCapability, implements[Forecaster]},
+	{schemata.GeocodingCapability, implements[Geocoder]},
+	{schemata.ReverseGeocodingCapability, implements[ReverseGeocoder]},
+	{schemata.HistoryCapability, implements[Historian]},
+	{schemata.AirQualityCapability, implements[AirQualityReporter]},
+	{schemata.AlertsCapability, implements[Alerter]},
+}
+
+func implements[C any](provider Provider) bool {
+	_, ok := provider.(C)
+	return ok
+}
+
+// Capabilities returns the capabilities of provider: fetching the current weather, which every provider does,
+// and those whose interface it implements, the very check chains pick capable providers by.
+func Capabilities(provider Provider) []schemata.Capability {
+	capabilities := []schemata.Capability{schemata.CurrentWeatherCapability}
+	for _, optional := range optionalCapabilities {
+		if optional.implemented(provider) {
+			capabilities = append(capabilities, optional.capability)
+		}
 	}
 
-	return fetchWeather, nil
+	return capabilities
 }
@@ -1,126 +1,193 @@
 package weather_api
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"testing"
+
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/conf"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_meteo"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/open_weather"
+	"github.com/AbolfazlAkhtari/weather-forecast/pkg/weather_api/schemata"
 )
 
-func TestLoadFetchWeatherByLocationFunc(t *testing.T) {
+type fakeProvider struct {
+	name      string
+	err       error
+	transient bool
+	calls     *int
+}
+
+func (p fakeProvider) Name() string {
+	return p.name
+}
+
+func (p fakeProvider) FetchCurrent(ctx context.Context, location schemata.Location) (*schemata.FetchWeatherResponse, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &schemata.FetchWeatherResponse{LocationName: location.CityName, Country: location.Country}, nil
+}
+
+func (p fakeProvider) HealthCheck(ctx context.Context) error {
+	return p.err
+}
+
+func (p fakeProvider) IsTransient(err error) bool {
+	return p.transient
+}
+
+func TestRegistryGet(t *testing.T) {
+	registry := LoadRegistry(conf.Config{}, nil)
+
 	tests := []struct {
-		name           string
-		provider       WeatherProvider
-		expectedError  bool
-		expectedResult bool
+		name          string
+		provider      WeatherProvider
+		expectedError bool
 	}{
 		{
-			name:           "should return function for valid OpenWeather provider",
-			provider:       OpenWeather,
-			expectedError:  false,
-			expectedResult: true,
+			name:          "should return provider for valid OpenWeather provider",
+			provider:      OpenWeather,
+			expectedError: false,
+		},
+		{
+			name:          "should return provider for valid OpenMeteo provider",
+			provider:      OpenMeteo,
+			expectedError: false,
 		},
 		{
-			name:           "should return error for invalid provider",
-			provider:       WeatherProvider("InvalidProvider"),
-			expectedError:  true,
-			expectedResult: false,
+			name:          "should return error for invalid provider",
+			provider:      WeatherProvider("InvalidProvider"),
+			expectedError: true,
 		},
 		{
-			name:           "should return error for empty provider",
-			provider:       WeatherProvider(""),
-			expectedError:  true,
-			expectedResult: false,
+			name:          "should return error for empty provider",
+			provider:      WeatherProvider(""),
+			expectedError: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := LoadFetchWeatherByLocationFunc(tt.provider)
+			result, err := registry.Get(tt.provider)
 
 			if tt.expectedError {
-				if err == nil {
-					t.Errorf("LoadFetchWeatherByLocationFunc() expected error but got none")
+				if !errors.Is(err, ProviderNotImplementedErr) {
+					t.Errorf("Registry.Get() error = %v, want %v", err, ProviderNotImplementedErr)
 				}
 				if result != nil {
-					t.Errorf("LoadFetchWeatherByLocationFunc() expected nil result but got %v", result)
-				}
-				// Check if the error message is correct
-				expectedErrMsg := "invalid weather provider name | provider not implemented"
-				if err.Error() != expectedErrMsg {
-					t.Errorf("LoadFetchWeatherByLocationFunc() error message = %v, want %v", err.Error(), expectedErrMsg)
+					t.Errorf("Registry.Get() expected nil result but got %v", result)
 				}
 			} else {
 				if err != nil {
-					t.Errorf("LoadFetchWeatherByLocationFunc() unexpected error = %v", err)
+					t.Errorf("Registry.Get() unexpected error = %v", err)
 				}
 				if result == nil {
-					t.Errorf("LoadFetchWeatherByLocationFunc() expected function but got nil")
+					t.Fatalf("Registry.Get() expected provider but got nil")
+				}
+				if WeatherProvider(result.Name()) != tt.provider {
+					t.Errorf("Registry.Get() provider name = %v, want %v", result.Name(), tt.provider)
 				}
 			}
 		})
 	}
 }
 
-func TestFetchWeatherByLocationFuncMap(t *testing.T) {
-	t.Run("should contain OpenWeather provider", func(t *testing.T) {
-		if _, exists := FetchWeatherByLocationFunc[OpenWeather]; !exists {
-			t.Errorf("FetchWeatherByLocationFunc map should contain OpenWeather provider")
-		}
-	})
+func TestRegistryRegister(t *testing.T) {
+	t.Run("should register a new provider", func(t *testing.T) {
+		registry := NewRegistry(conf.CircuitBreakerConfig{}, nil)
+		registry.Register(fakeProvider{name: "Fake"})
 
-	t.Run("should not contain invalid providers", func(t *testing.T) {
-		invalidProviders := []WeatherProvider{
-			"WeatherAPI",
-			"AccuWeather",
-			"",
+		provider, err := registry.Get("Fake")
+		if err != nil {
+			t.Fatalf("Registry.Get() unexpected error = %v", err)
 		}
 
-		for _, provider := range invalidProviders {
-			if _, exists := FetchWeatherByLocationFunc[provider]; exists {
-				t.Errorf("FetchWeatherByLocationFunc map should not contain provider: %s", provider)
-			}
+		result, err := provider.FetchCurrent(context.Background(), schemata.Location{CityName: "London", Country: "GB"})
+		if err != nil {
+			t.Fatalf("FetchCurrent() unexpected error = %v", err)
+		}
+		if result.LocationName != "London" {
+			t.Errorf("FetchCurrent() location = %v, want London", result.LocationName)
 		}
 	})
-}
 
-func TestWeatherProviderConstants(t *testing.T) {
-	t.Run("should have correct OpenWeather constant value", func(t *testing.T) {
-		expected := WeatherProvider("OpenWeather")
-		if OpenWeather != expected {
-			t.Errorf("OpenWeather constant = %v, want %v", OpenWeather, expected)
+	t.Run("should replace a provider registered under the same name", func(t *testing.T) {
+		registry := LoadRegistry(conf.Config{}, nil)
+		registry.Register(fakeProvider{name: string(OpenWeather)})
+
+		provider, err := registry.Get(OpenWeather)
+		if err != nil {
+			t.Fatalf("Registry.Get() unexpected error = %v", err)
+		}
+		if _, ok := provider.(fakeProvider); !ok {
+			t.Errorf("Registry.Get() = %T, want fakeProvider", provider)
 		}
 	})
 }
 
-// Integration test to verify the function is returned correctly
-func TestLoadFetchWeatherByLocationFuncIntegration(t *testing.T) {
-	t.Run("should return function for OpenWeather", func(t *testing.T) {
-		fetchFunc, err := LoadFetchWeatherByLocationFunc(OpenWeather)
-		if err != nil {
-			t.Fatalf("LoadFetchWeatherByLocationFunc() failed: %v", err)
-		}
+func TestRegistryNames(t *testing.T) {
+	registry := NewRegistry(conf.CircuitBreakerConfig{}, nil)
+	registry.Register(fakeProvider{name: "B"})
+	registry.Register(fakeProvider{name: "A"})
 
-		if fetchFunc == nil {
-			t.Fatal("LoadFetchWeatherByLocationFunc() returned nil function")
-		}
+	names := registry.Names()
+	if len(names) != 2 || names[0] != "A" || names[1] != "B" {
+		t.Errorf("Registry.Names() = %v, want [A B]", names)
+	}
+}
 
-		// Assert that fetchFunc is a function (not nil)
-		if fetchFunc == nil {
-			t.Error("Expected non-nil function, got nil")
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		provider Provider
+		expected []schemata.Capability
+	}{
+		{
+			provider: open_weather.NewProvider(conf.Config{}),
+			expected: []schemata.Capability{
+				schemata.CurrentWeatherCapability,
+				schemata.ForecastCapability,
+				schemata.GeocodingCapability,
+				schemata.ReverseGeocodingCapability,
+				schemata.AirQualityCapability,
+				schemata.AlertsCapability,
+			},
+		},
+		{
+			provider: open_meteo.NewProvider(conf.Config{}),
+			expected: []schemata.Capability{
+				schemata.CurrentWeatherCapability,
+				schemata.GeocodingCapability,
+				schemata.HistoryCapability,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Capabilities(tt.provider); !slices.Equal(got, tt.expected) {
+			t.Errorf("Capabilities(%v) = %v, want %v", tt.provider.Name(), got, tt.expected)
 		}
-	})
+	}
 }
 
-func TestLoadFetchWeatherByLocationFuncErrorHandling(t *testing.T) {
-	t.Run("should return specific error for invalid provider", func(t *testing.T) {
-		invalidProvider := WeatherProvider("NonExistentProvider")
-
-		_, err := LoadFetchWeatherByLocationFunc(invalidProvider)
-		if err == nil {
-			t.Fatal("Expected error for invalid provider, got nil")
+func TestWeatherProviderConstants(t *testing.T) {
+	t.Run("should have correct OpenWeather constant value", func(t *testing.T) {
+		expected := WeatherProvider("OpenWeather")
+		if OpenWeather != expected {
+			t.Errorf("OpenWeather constant = %v, want %v", OpenWeather, expected)
 		}
+	})
 
-		// Check if it's the expected error type
-		if err.Error() != "invalid weather provider name | provider not implemented" {
-			t.Errorf("Expected specific error message, got: %v", err)
+	t.Run("should have correct OpenMeteo constant value", func(t *testing.T) {
+		expected := WeatherProvider("OpenMeteo")
+		if OpenMeteo != expected {
+			t.Errorf("OpenMeteo constant = %v, want %v", OpenMeteo, expected)
 		}
 	})
 }
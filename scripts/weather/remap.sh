@@ -0,0 +1,4 @@
+#!/usr/bin/env bash
+
+export $(grep -v '^#' .env | xargs)
+go run cmd/remap/main.go "$@"